@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ForEachMaster 在每个主节点上并发执行 fn，等待全部完成后用 errors.Join 汇总所有节点返回的错误
+// 单节点模式下直接对 Client 执行 fn。fn 中返回的错误不会中断其它节点的执行，需要时应在错误中自行带上节点地址
+func ForEachMaster(ctx context.Context, fn func(ctx context.Context, node *redis.Client) error) error {
+	if config.IsCluster {
+		var mu sync.Mutex
+		var errs []error
+		err := ClusterClient.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+			if err := fn(ctx, master); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	} else {
+		return fn(ctx, Client.(*redis.Client))
+	}
+}
+
+// ResetStats 执行 CONFIG RESETSTAT，清零 INFO 中的统计计数（处理的命令数、keyspace 命中/未命中等）
+// Cluster 模式下对所有主节点执行，并汇总所有节点的错误
+func ResetStats(ctx context.Context) error {
+	return ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		if err := node.ConfigResetStat(ctx).Err(); err != nil {
+			return fmt.Errorf("failed to reset stats on %s: %v", node.Options().Addr, err)
+		}
+		return nil
+	})
+}
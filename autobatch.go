@@ -0,0 +1,187 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// autoBatchMaxKeys 是自动批处理中累计多少个 key 时立即发送，而不等待窗口结束
+const autoBatchMaxKeys = 100
+
+type autoBatchKey struct{}
+
+// BeginAutoBatch 返回一个开启了自动批处理的 context：在该 context 下并发调用的 Get/MGet 会被合并，
+// 每隔 window（或累计 autoBatchMaxKeys 个 key 时）以 MGET 批量发送，再把结果分发给各个调用方。
+// 代价是每次调用最多增加一个 window 的延迟，适合高并发下大量独立的单 key 读取。
+// Cluster 模式下 MGET 要求所有 key 位于同一个哈希槽，因此合并后的 key 会按槽分组发送，只有同槽的 key 能真正合并为一次请求
+// 批量请求使用不会被取消的 ctx 副本发送，单个调用方的 ctx 结束只会让该调用方提前返回
+func BeginAutoBatch(ctx context.Context, window time.Duration) context.Context {
+	b := &autoBatcher{ctx: context.WithoutCancel(ctx), window: window}
+	return context.WithValue(ctx, autoBatchKey{}, b)
+}
+
+// autoBatcherFrom 返回 ctx 中的自动批处理器，没有时返回 nil
+func autoBatcherFrom(ctx context.Context) *autoBatcher {
+	b, _ := ctx.Value(autoBatchKey{}).(*autoBatcher)
+	return b
+}
+
+type autoBatcher struct {
+	ctx    context.Context
+	window time.Duration
+
+	mu          sync.Mutex
+	pending     []*batchRequest
+	pendingKeys int
+	timer       *time.Timer
+}
+
+type batchRequest struct {
+	keys []string
+	done chan batchResult
+}
+
+type batchResult struct {
+	values []interface{}
+	err    error
+}
+
+// fetch 将 keys 加入当前批次并等待结果，返回值与 MGET 相同，不存在的 key 为 nil
+func (b *autoBatcher) fetch(ctx context.Context, keys []string) ([]interface{}, error) {
+	req := &batchRequest{keys: keys, done: make(chan batchResult, 1)}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, req)
+	b.pendingKeys += len(keys)
+	if b.pendingKeys >= autoBatchMaxKeys {
+		batch := b.take()
+		b.mu.Unlock()
+		go b.flush(batch)
+	} else {
+		if b.timer == nil {
+			b.timer = time.AfterFunc(b.window, func() {
+				b.mu.Lock()
+				batch := b.take()
+				b.mu.Unlock()
+				b.flush(batch)
+			})
+		}
+		b.mu.Unlock()
+	}
+
+	select {
+	case result := <-req.done:
+		return result.values, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// take 取出当前批次并重置计时器，调用方需持有 b.mu
+func (b *autoBatcher) take() []*batchRequest {
+	batch := b.pending
+	b.pending = nil
+	b.pendingKeys = 0
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	return batch
+}
+
+// flush 对批次中的所有 key 去重后执行 MGET，并把结果分发给各个请求
+func (b *autoBatcher) flush(batch []*batchRequest) {
+	if len(batch) == 0 {
+		return
+	}
+	seen := make(map[string]struct{})
+	var keys []string
+	for _, req := range batch {
+		for _, key := range req.keys {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	values, err := mgetBySlot(b.ctx, keys)
+	for _, req := range batch {
+		if err != nil {
+			req.done <- batchResult{err: err}
+			continue
+		}
+		result := make([]interface{}, len(req.keys))
+		for i, key := range req.keys {
+			result[i] = values[key]
+		}
+		req.done <- batchResult{values: result}
+	}
+}
+
+// MGet 批量读取字符串 key，返回值按 keys 顺序排列，不存在的 key 为 nil
+// Cluster 模式下按哈希槽分组分别执行 MGET，不同槽之间不是原子的；在 BeginAutoBatch 的 context 下会与其他调用合并
+func MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	if err := checkKeys(keys...); err != nil {
+		return nil, err
+	}
+	if b := autoBatcherFrom(ctx); b != nil {
+		values, err := b.fetch(ctx, keys)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get values of keys: %v", err)
+		}
+		return values, nil
+	}
+	values, err := mgetBySlot(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get values of keys: %v", err)
+	}
+	result := make([]interface{}, len(keys))
+	for i, key := range keys {
+		result[i] = values[key]
+	}
+	return result, nil
+}
+
+// mgetBySlot 按哈希槽分组执行 MGET，返回 key -> 值，不存在的 key 为 nil
+// 所有分组在同一个 pipeline 中发送，Cluster 模式下 ClusterClient 会按节点并发执行
+func mgetBySlot(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	groups := groupKeysBySlot(keys)
+	cmds := make([]*redis.SliceCmd, len(groups))
+	_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, group := range groups {
+			cmds[i] = pipe.MGet(ctx, group...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{}, len(keys))
+	for i, group := range groups {
+		for j, key := range group {
+			values[key] = cmds[i].Val()[j]
+		}
+	}
+	return values, nil
+}
+
+// getBatched 在自动批处理下读取单个 key，语义与 Get 一致：key 不存在时返回包装了 redis.Nil 的错误
+func getBatched(ctx context.Context, b *autoBatcher, key string) (string, error) {
+	values, err := b.fetch(ctx, []string{key})
+	if err != nil {
+		if value, ok := localFallbackGet(ctx, key, err); ok {
+			return value, nil
+		}
+		return "", fmt.Errorf("failed to get value of key %s: %v", key, err)
+	}
+	value, ok := values[0].(string)
+	if !ok {
+		return "", fmt.Errorf("failed to get value of key %s: %w", key, redis.Nil)
+	}
+	return value, nil
+}
@@ -0,0 +1,229 @@
+package redis
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// backupMagic 是备份文件头部的魔数
+	backupMagic = "RCBK"
+	// backupVersion 是当前的备份格式版本，RestoreFromFile 只接受相同版本
+	backupVersion uint16 = 1
+)
+
+// BackupToFile 扫描匹配 pattern 的 key，将每个 key 的 DUMP 结果和剩余 TTL 以流式方式写入 gzip 压缩的文件，返回备份的 key 数量
+// 文件格式：头部为魔数 "RCBK"、uint16 格式版本、int64 备份时间（Unix 秒）；
+// 之后每条记录为 uint32 key 长度、key、int64 剩余 TTL 毫秒（0 表示不过期）、uint32 DUMP 长度、DUMP 数据，均为大端序。
+// 扫描期间被删除的 key 会被跳过
+func BackupToFile(ctx context.Context, pattern, filePath string) (int, error) {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create backup file %s: %v", filePath, err)
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	w := bufio.NewWriter(gz)
+
+	if _, err := w.WriteString(backupMagic); err != nil {
+		return 0, fmt.Errorf("failed to write backup header: %v", err)
+	}
+	if err := writeBinary(w, backupVersion, time.Now().Unix()); err != nil {
+		return 0, fmt.Errorf("failed to write backup header: %v", err)
+	}
+
+	var mu sync.Mutex
+	count := 0
+	err = Scan(ctx, pattern, 100, func(keys []string) error {
+		for _, group := range groupKeysBySlot(keys) {
+			dumps := make([]*redis.StringCmd, len(group))
+			ttls := make([]*redis.DurationCmd, len(group))
+			_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+				for i, key := range group {
+					dumps[i] = pipe.Dump(ctx, key)
+					ttls[i] = pipe.PTTL(ctx, key)
+				}
+				return nil
+			})
+			if err != nil && err != redis.Nil {
+				return fmt.Errorf("failed to dump keys: %v", err)
+			}
+
+			// Pipelined 只返回第一个错误，需要逐条检查，redis.Nil 表示 key 在扫描期间被删除
+			mu.Lock()
+			for i, key := range group {
+				dump, err := dumps[i].Result()
+				if err == redis.Nil {
+					continue
+				}
+				if err != nil {
+					mu.Unlock()
+					return fmt.Errorf("failed to dump key %s: %v", key, err)
+				}
+				ttl, err := ttls[i].Result()
+				if err != nil {
+					mu.Unlock()
+					return fmt.Errorf("failed to get ttl of key %s: %v", key, err)
+				}
+				if ttl == -2 {
+					// DUMP 之后 key 已过期或被删除
+					continue
+				}
+				if ttl < 0 {
+					ttl = 0
+				}
+				if err := writeRecord(w, key, ttl, dump); err != nil {
+					mu.Unlock()
+					return fmt.Errorf("failed to write backup record of key %s: %v", key, err)
+				}
+				count++
+			}
+			mu.Unlock()
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	if err := w.Flush(); err != nil {
+		return count, fmt.Errorf("failed to write backup file %s: %v", filePath, err)
+	}
+	if err := gz.Close(); err != nil {
+		return count, fmt.Errorf("failed to write backup file %s: %v", filePath, err)
+	}
+	return count, file.Close()
+}
+
+// RestoreFromFile 从 BackupToFile 生成的文件中流式恢复 key，返回恢复的 key 数量
+// 带 TTL 的 key 的 TTL 会扣除备份至今经过的时间，按备份时间计算已经过期的 key 会被跳过
+// replace 为 true 时覆盖已存在的 key，否则跳过已存在的 key；文件格式版本不一致时返回错误
+func RestoreFromFile(ctx context.Context, filePath string, replace bool) (int, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open backup file %s: %v", filePath, err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read backup file %s: %v", filePath, err)
+	}
+	defer gz.Close()
+	r := bufio.NewReader(gz)
+
+	magic := make([]byte, len(backupMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != backupMagic {
+		return 0, fmt.Errorf("invalid backup file %s: bad header", filePath)
+	}
+	var version uint16
+	var createdAt int64
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return 0, fmt.Errorf("invalid backup file %s: %v", filePath, err)
+	}
+	if version != backupVersion {
+		return 0, fmt.Errorf("unsupported backup version %d in %s, expected %d", version, filePath, backupVersion)
+	}
+	if err := binary.Read(r, binary.BigEndian, &createdAt); err != nil {
+		return 0, fmt.Errorf("invalid backup file %s: %v", filePath, err)
+	}
+
+	elapsed := time.Since(time.Unix(createdAt, 0))
+	count := 0
+	for {
+		key, ttl, dump, err := readRecord(r)
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("invalid backup file %s: %v", filePath, err)
+		}
+		if ttl > 0 {
+			if ttl -= elapsed; ttl <= 0 {
+				continue
+			}
+		}
+
+		if replace {
+			err = Client.RestoreReplace(ctx, key, ttl, dump).Err()
+		} else {
+			err = Client.Restore(ctx, key, ttl, dump).Err()
+			if err != nil && strings.HasPrefix(err.Error(), "BUSYKEY") {
+				continue
+			}
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to restore key %s: %v", key, err)
+		}
+		invalidateLocal(key)
+		count++
+	}
+}
+
+// writeRecord 写入一条备份记录
+func writeRecord(w io.Writer, key string, ttl time.Duration, dump string) error {
+	if err := writeBinary(w, uint32(len(key))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, key); err != nil {
+		return err
+	}
+	if err := writeBinary(w, ttl.Milliseconds(), uint32(len(dump))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, dump)
+	return err
+}
+
+// readRecord 读取一条备份记录，文件在记录边界结束时返回 io.EOF
+func readRecord(r io.Reader) (key string, ttl time.Duration, dump string, err error) {
+	var keyLen uint32
+	if err := binary.Read(r, binary.BigEndian, &keyLen); err != nil {
+		return "", 0, "", err
+	}
+	keyBuf := make([]byte, keyLen)
+	if _, err := io.ReadFull(r, keyBuf); err != nil {
+		return "", 0, "", unexpectedEOF(err)
+	}
+	var ttlMs int64
+	var dumpLen uint32
+	if err := binary.Read(r, binary.BigEndian, &ttlMs); err != nil {
+		return "", 0, "", unexpectedEOF(err)
+	}
+	if err := binary.Read(r, binary.BigEndian, &dumpLen); err != nil {
+		return "", 0, "", unexpectedEOF(err)
+	}
+	dumpBuf := make([]byte, dumpLen)
+	if _, err := io.ReadFull(r, dumpBuf); err != nil {
+		return "", 0, "", unexpectedEOF(err)
+	}
+	return string(keyBuf), time.Duration(ttlMs) * time.Millisecond, string(dumpBuf), nil
+}
+
+// writeBinary 依次以大端序写入 values
+func writeBinary(w io.Writer, values ...interface{}) error {
+	for _, v := range values {
+		if err := binary.Write(w, binary.BigEndian, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unexpectedEOF 将记录中途的 io.EOF 转换为 io.ErrUnexpectedEOF，避免被当成正常结束
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+)
+
+// BitPos 返回位图中第一个值为 bit（0 或 1）的位的位置
+// pos 可选传入 start、end，表示按字节计算的查找范围；找不到时返回 -1
+func BitPos(ctx context.Context, key string, bit int, pos ...int64) (int64, error) {
+	if bit != 0 && bit != 1 {
+		return 0, fmt.Errorf("invalid bit %d: must be 0 or 1", bit)
+	}
+	if len(pos) > 2 {
+		return 0, fmt.Errorf("too many positions for BITPOS: expected at most start and end, got %d", len(pos))
+	}
+	if err := checkKeys(key); err != nil {
+		return 0, err
+	}
+	if config.IsCluster {
+		result, err := ClusterClient.BitPos(ctx, key, int64(bit), pos...).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to find bit %d in key %s: %v", bit, key, err)
+		}
+		return result, nil
+	} else {
+		result, err := Client.BitPos(ctx, key, int64(bit), pos...).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to find bit %d in key %s: %v", bit, key, err)
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,53 @@
+package redis
+
+import "testing"
+
+func TestBitPos(t *testing.T) {
+	ctx := setupTestRedis(t)
+	key, zeros := "test:bitpos", "test:bitpos:zeros"
+	cleanupKeys(t, ctx, key, zeros)
+
+	// 11111111 11110000 00000000
+	if err := Client.Set(ctx, key, "\xff\xf0\x00", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Client.Set(ctx, zeros, "\x00\x00", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		bit  int
+		pos  []int64
+		want int64
+	}{
+		{"first set bit", key, 1, nil, 0},
+		{"first clear bit", key, 0, nil, 12},
+		{"set bit in range", key, 1, []int64{1, 2}, 8},
+		{"clear bit in range", key, 0, []int64{1}, 12},
+		{"no set bit in range", key, 1, []int64{2, 2}, -1},
+		{"no set bit", zeros, 1, nil, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BitPos(ctx, tt.key, tt.bit, tt.pos...)
+			if err != nil {
+				t.Fatalf("BitPos: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("BitPos(%s, %d, %v) = %d; want %d", tt.key, tt.bit, tt.pos, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBitPosInvalidArgs(t *testing.T) {
+	ctx := setupTestRedis(t)
+	if _, err := BitPos(ctx, "test:bitpos", 2); err == nil {
+		t.Fatal("BitPos with bit 2: want error")
+	}
+	if _, err := BitPos(ctx, "test:bitpos", 1, 0, 1, 2); err == nil {
+		t.Fatal("BitPos with three positions: want error")
+	}
+}
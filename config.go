@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// ConfigBuilder 以链式调用的方式构造 RedisConfig，用于不使用 viper / 配置文件的场景
+type ConfigBuilder struct {
+	cfg RedisConfig
+}
+
+// NewConfig 创建一个空的 ConfigBuilder
+func NewConfig() *ConfigBuilder {
+	return &ConfigBuilder{}
+}
+
+// WithAddr 设置单机模式的地址
+func (b *ConfigBuilder) WithAddr(addr string) *ConfigBuilder {
+	b.cfg.Addr = addr
+	return b
+}
+
+// WithPassword 设置密码
+func (b *ConfigBuilder) WithPassword(password string) *ConfigBuilder {
+	b.cfg.Password = password
+	return b
+}
+
+// WithDB 设置单机模式使用的 DB
+func (b *ConfigBuilder) WithDB(db int) *ConfigBuilder {
+	b.cfg.DB = db
+	return b
+}
+
+// WithCluster 开启 Cluster 模式并设置节点地址
+func (b *ConfigBuilder) WithCluster(nodes ...string) *ConfigBuilder {
+	b.cfg.IsCluster = true
+	b.cfg.Nodes = append(b.cfg.Nodes, nodes...)
+	return b
+}
+
+// WithTLS 设置 TLS 配置
+func (b *ConfigBuilder) WithTLS(tlsConfig *tls.Config) *ConfigBuilder {
+	b.cfg.TLSConfig = tlsConfig
+	return b
+}
+
+// WithReplicaAddr 设置单机模式下只读客户端连接的副本地址
+func (b *ConfigBuilder) WithReplicaAddr(addr string) *ConfigBuilder {
+	b.cfg.ReplicaAddr = addr
+	return b
+}
+
+// WithFallbackAddrs 设置单机模式下初始连接失败时依次尝试的备用地址
+func (b *ConfigBuilder) WithFallbackAddrs(addrs ...string) *ConfigBuilder {
+	b.cfg.FallbackAddrs = append(b.cfg.FallbackAddrs, addrs...)
+	return b
+}
+
+// WithLocalFallback 开启本地降级缓存，size 小于等于 0 时使用默认容量
+func (b *ConfigBuilder) WithLocalFallback(size int) *ConfigBuilder {
+	b.cfg.EnableLocalFallback = true
+	b.cfg.LocalFallbackSize = size
+	return b
+}
+
+// WithAllowDestructiveWildcard 允许修改类批量操作使用只由通配符组成的模式（如 "*"）
+func (b *ConfigBuilder) WithAllowDestructiveWildcard(allow bool) *ConfigBuilder {
+	b.cfg.AllowDestructiveWildcard = allow
+	return b
+}
+
+// Build 校验并返回构造好的配置，可直接传给 InitRedisClientWithConfig
+func (b *ConfigBuilder) Build() (RedisConfig, error) {
+	if b.cfg.IsCluster {
+		if len(b.cfg.Nodes) == 0 {
+			return RedisConfig{}, fmt.Errorf("invalid config: cluster mode requires at least one node")
+		}
+		if b.cfg.DB != 0 {
+			return RedisConfig{}, fmt.Errorf("invalid config: cluster mode only supports db 0, got %d", b.cfg.DB)
+		}
+	} else if b.cfg.Addr == "" {
+		return RedisConfig{}, fmt.Errorf("invalid config: single node mode requires an addr")
+	}
+	if b.cfg.DB < 0 {
+		return RedisConfig{}, fmt.Errorf("invalid config: db must not be negative, got %d", b.cfg.DB)
+	}
+	return b.cfg, nil
+}
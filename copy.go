@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// deepCopyBatchSize 是 DeepCopy 每条写命令携带的最大元素数
+const deepCopyBatchSize = 1000
+
+// DeepCopy 按类型读取 src 的内容（GET/LRANGE/SMEMBERS/HGETALL/ZRANGE/XRANGE）并用对应的写命令重建到 dst，保留 TTL
+// dst 已存在时会被覆盖。由于在逻辑层面复制，可以跨哈希槽/节点使用，适合 DUMP/RESTORE 不可用（超大 key、版本不兼容）的场景。
+// 注意：读取与写入不是原子的，复制期间 src 的修改可能不会反映到 dst；dst 的写入在一个 MULTI 中完成
+func DeepCopy(ctx context.Context, src, dst string) error {
+	if err := checkKeys(src, dst); err != nil {
+		return err
+	}
+	typ, err := Type(ctx, src)
+	if err != nil {
+		return err
+	}
+	value, err := GetAny(ctx, src)
+	if err != nil {
+		return err
+	}
+	ttl, err := Client.PTTL(ctx, src).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get ttl of key %s: %v", src, err)
+	}
+
+	_, err = Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, dst)
+		switch v := value.(type) {
+		case string:
+			pipe.Set(ctx, dst, v, 0)
+		case []string:
+			// list 与 set 都返回 []string，按类型选择写命令
+			for start := 0; start < len(v); start += deepCopyBatchSize {
+				batch := toInterfaces(v[start:min(start+deepCopyBatchSize, len(v))])
+				if typ == "list" {
+					pipe.RPush(ctx, dst, batch...)
+				} else {
+					pipe.SAdd(ctx, dst, batch...)
+				}
+			}
+		case map[string]string:
+			values := make([]interface{}, 0, deepCopyBatchSize*2)
+			for field, val := range v {
+				values = append(values, field, val)
+				if len(values) >= deepCopyBatchSize*2 {
+					pipe.HSet(ctx, dst, values...)
+					values = make([]interface{}, 0, deepCopyBatchSize*2)
+				}
+			}
+			if len(values) > 0 {
+				pipe.HSet(ctx, dst, values...)
+			}
+		case []redis.Z:
+			for start := 0; start < len(v); start += deepCopyBatchSize {
+				pipe.ZAdd(ctx, dst, v[start:min(start+deepCopyBatchSize, len(v))]...)
+			}
+		case []redis.XMessage:
+			for _, msg := range v {
+				pipe.XAdd(ctx, &redis.XAddArgs{Stream: dst, ID: msg.ID, Values: msg.Values})
+			}
+		default:
+			return fmt.Errorf("unsupported value type %T of key %s", value, src)
+		}
+		if ttl > 0 {
+			pipe.PExpire(ctx, dst, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy key %s to %s: %v", src, dst, err)
+	}
+	invalidateLocal(dst)
+	return nil
+}
+
+// Copy 将 src 复制到 dst（dst 已存在时覆盖）。preserveTTL 为 true 时 dst 使用 src 的剩余 TTL，为 false 时 dst 不过期
+// 同一哈希槽（或单节点模式）下的执行顺序为：PTTL src、COPY src dst REPLACE、PEXPIRE/PERSIST dst；
+// 跨哈希槽或服务端不支持 COPY（< 6.2）时为：PTTL src、DUMP src、RESTORE dst ttl REPLACE。
+// 注意：整个过程不是原子的，期间 src 的 TTL 被修改时 dst 使用的是修改前读取到的值，COPY 与设置 TTL 之间 dst 短暂带有 src 复制来的 TTL
+func Copy(ctx context.Context, src, dst string, preserveTTL bool) error {
+	if err := checkKeys(src, dst); err != nil {
+		return err
+	}
+	ttl, err := Client.PTTL(ctx, src).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get ttl of key %s: %v", src, err)
+	}
+	if ttl == -2 {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, src)
+	}
+	if ttl < 0 || !preserveTTL {
+		ttl = 0
+	}
+
+	if same, _ := SameSlot(src, dst); !config.IsCluster || same {
+		// COPY 不放入 MULTI：事务中排队失败的 unknown command 会被 EXECABORT 掩盖，无法判断是否需要降级
+		copied, err := Client.Copy(ctx, src, dst, config.DB, true).Result()
+		switch {
+		case err == nil && copied == 0:
+			return fmt.Errorf("%w: %s", ErrKeyNotFound, src)
+		case err == nil:
+			if ttl > 0 {
+				err = Client.PExpire(ctx, dst, ttl).Err()
+			} else {
+				err = Client.Persist(ctx, dst).Err()
+			}
+			if err != nil {
+				return fmt.Errorf("failed to set ttl of key %s: %v", dst, err)
+			}
+			invalidateLocal(dst)
+			return nil
+		case !isUnknownCommand(err):
+			return fmt.Errorf("failed to copy key %s to %s: %v", src, dst, err)
+		}
+	}
+
+	dump, err := Client.Dump(ctx, src).Result()
+	if err == redis.Nil {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, src)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to dump key %s: %v", src, err)
+	}
+	if err := Client.RestoreReplace(ctx, dst, ttl, dump).Err(); err != nil {
+		return fmt.Errorf("failed to restore key %s: %v", dst, err)
+	}
+	invalidateLocal(dst)
+	return nil
+}
+
+// toInterfaces 将 []string 转换为 []interface{}，用于可变参数的写命令
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCopyPreservesTTL(t *testing.T) {
+	ctx := setupTestRedis(t)
+	src, dst := "test:copy:{ttl}:src", "test:copy:{ttl}:dst"
+	cleanupKeys(t, ctx, src, dst)
+
+	if err := Client.Set(ctx, src, "v", 10*time.Second).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Copy(ctx, src, dst, true); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	value, err := Client.Get(ctx, dst).Result()
+	if err != nil || value != "v" {
+		t.Fatalf("Get(dst) = %q, %v; want \"v\"", value, err)
+	}
+	ttl, err := Client.PTTL(ctx, dst).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl < 9*time.Second || ttl > 10*time.Second {
+		t.Fatalf("PTTL(dst) = %v; want close to 10s", ttl)
+	}
+}
+
+func TestCopyWithoutTTL(t *testing.T) {
+	ctx := setupTestRedis(t)
+	src, dst := "test:copy:{nottl}:src", "test:copy:{nottl}:dst"
+	cleanupKeys(t, ctx, src, dst)
+
+	if err := Client.Set(ctx, src, "v", 10*time.Second).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Copy(ctx, src, dst, false); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	ttl, err := Client.PTTL(ctx, dst).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl != -1 {
+		t.Fatalf("PTTL(dst) = %v; want -1 (no expiry)", ttl)
+	}
+}
+
+func TestCopyMissingSource(t *testing.T) {
+	ctx := setupTestRedis(t)
+	src, dst := "test:copy:{missing}:src", "test:copy:{missing}:dst"
+	cleanupKeys(t, ctx, src, dst)
+
+	if err := Copy(ctx, src, dst, true); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("Copy missing source: err = %v; want ErrKeyNotFound", err)
+	}
+}
@@ -0,0 +1,153 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// drainTimeout 是 ctx 没有 deadline 时 DrainNode 等待进行中命令结束的最长时间
+	drainTimeout = 30 * time.Second
+	// drainPollInterval 是 DrainNode 检查进行中命令数的间隔
+	drainPollInterval = 100 * time.Millisecond
+)
+
+// drainingNodes 记录正在下线的节点地址；节点离开拓扑后由 readOnlyClusterSlots 清除，之后以相同地址加入的节点可以正常路由
+var drainingNodes sync.Map
+
+// DrainNode 将节点标记为正在下线，用于计划内移除 Cluster 节点前的流量排空
+// 标记后只读客户端（InitReadOnlyClient）刷新拓扑时会排除该副本节点，读请求转移到其他节点；
+// 随后等待主客户端与只读客户端到该节点的进行中命令（连接池中正在使用的连接）降为 0，期间打印进行中的数量，
+// 直到 ctx 结束（没有 deadline 时最多等待 drainTimeout），最后刷新主客户端与只读客户端的拓扑。
+// 主节点承担写入，无法被排空，下线主节点前需要先完成故障转移。addr 需与 CLUSTER SLOTS 中的地址一致。
+// 排空失败（超时或出错）时会撤销标记；排空成功后如果决定不下线该节点，需要调用 UndrainNode 恢复路由。
+// 单机模式下返回 ErrSingleNodeOnly
+func DrainNode(ctx context.Context, addr string) (err error) {
+	if !config.IsCluster {
+		return ErrSingleNodeOnly
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, drainTimeout)
+		defer cancel()
+	}
+
+	drainingNodes.Store(addr, struct{}{})
+	reloadTopology(ctx)
+	defer func() {
+		if err != nil {
+			drainingNodes.Delete(addr)
+			reloadTopology(context.WithoutCancel(ctx))
+		}
+	}()
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	last := -1
+	for {
+		inFlight, err := nodeInFlight(ctx, addr)
+		if err != nil {
+			return err
+		}
+		if inFlight != last {
+			fmt.Printf("Draining node %s: %d in-flight\n", addr, inFlight)
+			last = inFlight
+		}
+		if inFlight == 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to drain node %s: %d still in flight: %v", addr, inFlight, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	reloadTopology(ctx)
+	fmt.Printf("Node %s drained\n", addr)
+	return nil
+}
+
+// UndrainNode 撤销 DrainNode 的标记并刷新拓扑，只读客户端重新将读请求路由到该节点
+// 单机模式下返回 ErrSingleNodeOnly
+func UndrainNode(ctx context.Context, addr string) error {
+	if !config.IsCluster {
+		return ErrSingleNodeOnly
+	}
+	drainingNodes.Delete(addr)
+	reloadTopology(ctx)
+	return nil
+}
+
+// nodeInFlight 返回主客户端与只读客户端到 addr 节点正在使用的连接数，节点已不在拓扑中时返回 0
+func nodeInFlight(ctx context.Context, addr string) (int, error) {
+	clients := []*redis.ClusterClient{ClusterClient}
+	if c, ok := readOnlyClient.(*redis.ClusterClient); ok {
+		clients = append(clients, c)
+	}
+
+	var mu sync.Mutex
+	inFlight := 0
+	for _, c := range clients {
+		err := c.ForEachShard(ctx, func(ctx context.Context, node *redis.Client) error {
+			if node.Options().Addr != addr {
+				return nil
+			}
+			stats := node.PoolStats()
+			mu.Lock()
+			inFlight += int(stats.TotalConns - stats.IdleConns)
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get pool stats of node %s: %v", addr, err)
+		}
+	}
+	return inFlight, nil
+}
+
+// reloadTopology 刷新主客户端与只读客户端的集群拓扑
+func reloadTopology(ctx context.Context) {
+	ClusterClient.ReloadState(ctx)
+	if c, ok := readOnlyClient.(*redis.ClusterClient); ok {
+		c.ReloadState(ctx)
+	}
+}
+
+// readOnlyClusterSlots 通过主客户端获取 CLUSTER SLOTS，并从每个槽的副本列表中移除正在下线的节点
+// 每个槽的第一个节点为主节点，始终保留；已经不在拓扑中的节点的下线标记会被清除
+func readOnlyClusterSlots(ctx context.Context) ([]redis.ClusterSlot, error) {
+	slots, err := ClusterClient.ClusterSlots(ctx).Result()
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool)
+	for _, slot := range slots {
+		for _, node := range slot.Nodes {
+			present[node.Addr] = true
+		}
+	}
+	drainingNodes.Range(func(addr, _ interface{}) bool {
+		if !present[addr.(string)] {
+			drainingNodes.Delete(addr)
+		}
+		return true
+	})
+	for i := range slots {
+		if len(slots[i].Nodes) < 2 {
+			continue
+		}
+		nodes := slots[i].Nodes[:1:1]
+		for _, node := range slots[i].Nodes[1:] {
+			if _, draining := drainingNodes.Load(node.Addr); !draining {
+				nodes = append(nodes, node)
+			}
+		}
+		slots[i].Nodes = nodes
+	}
+	return slots, nil
+}
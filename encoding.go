@@ -0,0 +1,199 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// embstrMaxLen 是字符串能使用 embstr 编码的最大长度（字节）
+const embstrMaxLen = 44
+
+// SuggestEncoding 根据 key 的类型、大小和 OBJECT ENCODING 给出内存优化建议
+// key 不存在时返回 ErrKeyNotFound
+func SuggestEncoding(ctx context.Context, key string) (string, error) {
+	typ, err := Type(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	encoding, err := Client.ObjectEncoding(ctx, key).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get encoding of key %s: %v", key, err)
+	}
+
+	switch typ {
+	case "string":
+		size, err := Client.StrLen(ctx, key).Result()
+		if err != nil {
+			return "", fmt.Errorf("failed to get length of key %s: %v", key, err)
+		}
+		switch encoding {
+		case "int":
+			return "this string is stored with int encoding; no change needed", nil
+		case "embstr":
+			return fmt.Sprintf("this string is %d bytes and uses embstr encoding; no change needed", size), nil
+		default:
+			if size <= embstrMaxLen {
+				return fmt.Sprintf("this string is %d bytes but stored as raw; rewriting it with a single SET (instead of APPEND/SETRANGE) allows embstr encoding", size), nil
+			}
+			return fmt.Sprintf("this is stored as raw string of %d bytes; values over %d bytes can't use embstr, consider compressing large values", size, embstrMaxLen), nil
+		}
+	case "hash":
+		size, err := Client.HLen(ctx, key).Result()
+		if err != nil {
+			return "", fmt.Errorf("failed to get length of key %s: %v", key, err)
+		}
+		entries := configInt(ctx, 128, "hash-max-listpack-entries", "hash-max-ziplist-entries")
+		value := configInt(ctx, 64, "hash-max-listpack-value", "hash-max-ziplist-value")
+		return suggestCompact("hash", "fields", encoding, "hashtable", size, entries, value), nil
+	case "zset":
+		size, err := Client.ZCard(ctx, key).Result()
+		if err != nil {
+			return "", fmt.Errorf("failed to get length of key %s: %v", key, err)
+		}
+		entries := configInt(ctx, 128, "zset-max-listpack-entries", "zset-max-ziplist-entries")
+		value := configInt(ctx, 64, "zset-max-listpack-value", "zset-max-ziplist-value")
+		return suggestCompact("sorted set", "members", encoding, "skiplist", size, entries, value), nil
+	case "set":
+		size, err := Client.SCard(ctx, key).Result()
+		if err != nil {
+			return "", fmt.Errorf("failed to get length of key %s: %v", key, err)
+		}
+		intsetEntries := configInt(ctx, 512, "set-max-intset-entries")
+		switch encoding {
+		case "intset":
+			return fmt.Sprintf("this set has %d integer members and uses intset encoding; keep it below %d members to stay compact", size, intsetEntries), nil
+		case "listpack":
+			return fmt.Sprintf("this set has %d members and uses listpack encoding; no change needed", size), nil
+		default:
+			if size <= intsetEntries {
+				return fmt.Sprintf("this set has %d members and uses %s encoding; if all members were integers it could use intset", size, encoding), nil
+			}
+			return fmt.Sprintf("this set has %d members and uses %s encoding; consider splitting into sets of at most %d members", size, encoding, intsetEntries), nil
+		}
+	case "list":
+		size, err := Client.LLen(ctx, key).Result()
+		if err != nil {
+			return "", fmt.Errorf("failed to get length of key %s: %v", key, err)
+		}
+		return fmt.Sprintf("this list has %d elements and uses %s encoding; no change needed", size, encoding), nil
+	default:
+		return fmt.Sprintf("this %s uses %s encoding; no suggestion available", typ, encoding), nil
+	}
+}
+
+// suggestCompact 为 hash / zset 这类有紧凑编码（listpack/ziplist）的类型生成建议
+func suggestCompact(typ, unit, encoding, largeEncoding string, size, maxEntries, maxValue int64) string {
+	if encoding != largeEncoding {
+		return fmt.Sprintf("this %s has %d %s and uses %s encoding; no change needed", typ, size, unit, encoding)
+	}
+	if size > maxEntries {
+		return fmt.Sprintf("this %s has %d %s and uses %s encoding; consider splitting into %ss of at most %d %s", typ, size, unit, encoding, typ, maxEntries, unit)
+	}
+	return fmt.Sprintf("this %s has %d %s and uses %s encoding; some element probably exceeds %d bytes, keep elements short to allow compact encoding", typ, size, unit, encoding, maxValue)
+}
+
+// configInt 依次读取 names 中的配置项，返回第一个存在的整数值；都不存在时返回 def
+// 用于兼容新旧版本的配置名（如 hash-max-listpack-entries / hash-max-ziplist-entries）
+func configInt(ctx context.Context, def int64, names ...string) int64 {
+	for _, name := range names {
+		result, err := Client.ConfigGet(ctx, name).Result()
+		if err != nil {
+			var redisErr redis.Error
+			if errors.As(err, &redisErr) {
+				continue
+			}
+			return def
+		}
+		if v, ok := result[name]; ok {
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+	return def
+}
+
+// intsetWarnRatio 是集合成员数达到 set-max-intset-entries 的多少比例时开始提示
+const intsetWarnRatio = 0.9
+
+// SetEncodingInfo 返回集合的编码（intset/listpack/hashtable）和成员数量，key 不存在时返回 ErrKeyNotFound
+func SetEncodingInfo(ctx context.Context, key string) (encoding string, memberCount int64, err error) {
+	typ, err := Type(ctx, key)
+	if err != nil {
+		return "", 0, err
+	}
+	if typ != "set" {
+		return "", 0, fmt.Errorf("key %s is a %s, not a set", key, typ)
+	}
+	encoding, err = Client.ObjectEncoding(ctx, key).Result()
+	if err == redis.Nil {
+		return "", 0, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get encoding of key %s: %v", key, err)
+	}
+	memberCount, err = Client.SCard(ctx, key).Result()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get size of key %s: %v", key, err)
+	}
+	return encoding, memberCount, nil
+}
+
+// CheckIntsetEncoding 检查存放整数 ID 的集合是否即将或已经失去 intset 编码，返回提示信息，没有问题时返回空字符串
+// 以下情况会给出提示：intset 集合的成员数接近 set-max-intset-entries；
+// 成员数未超过阈值却不是 intset 编码（存在非整数成员，会指出其中一个）；成员数已超过阈值
+func CheckIntsetEncoding(ctx context.Context, key string) (string, error) {
+	encoding, count, err := SetEncodingInfo(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	maxEntries := configInt(ctx, 512, "set-max-intset-entries")
+
+	if encoding == "intset" {
+		if float64(count) >= float64(maxEntries)*intsetWarnRatio {
+			return fmt.Sprintf("set %s has %d members, close to set-max-intset-entries %d; it will convert to hashtable encoding beyond that", key, count, maxEntries), nil
+		}
+		return "", nil
+	}
+	if count > maxEntries {
+		return fmt.Sprintf("set %s has %d members, exceeding set-max-intset-entries %d, and uses %s encoding", key, count, maxEntries, encoding), nil
+	}
+
+	member, err := findNonIntegerMember(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if member == "" {
+		return fmt.Sprintf("set %s uses %s encoding with %d integer members; it was probably larger before", key, encoding, count), nil
+	}
+	return fmt.Sprintf("set %s uses %s encoding because member %q is not an integer", key, encoding, member), nil
+}
+
+// findNonIntegerMember 通过 SSCAN 找到集合中第一个无法以 intset 存储的成员，全部为整数时返回空字符串
+func findNonIntegerMember(ctx context.Context, key string) (string, error) {
+	var cursor uint64 = 0
+	for {
+		members, next, err := Client.SScan(ctx, key, cursor, "", 100).Result()
+		if err != nil {
+			return "", fmt.Errorf("failed to scan members of set %s: %v", key, err)
+		}
+		for _, member := range members {
+			n, err := strconv.ParseInt(member, 10, 64)
+			if err != nil || strconv.FormatInt(n, 10) != member {
+				return member, nil
+			}
+		}
+		if next == 0 {
+			return "", nil
+		}
+		cursor = next
+	}
+}
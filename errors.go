@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrKeyNotFound 表示 key 不存在
+var ErrKeyNotFound = errors.New("key does not exist")
+
+// ErrCrossSlot 表示 Cluster 模式下多 key 命令的 key 不在同一个哈希槽
+var ErrCrossSlot = errors.New("keys must hash to the same slot in cluster mode")
+
+// isUnknownCommand 判断错误是否为服务端不支持该命令（旧版本 Redis）
+func isUnknownCommand(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "unknown command")
+}
+
+// ErrQuorumNotReached 表示在超时时间内确认写入的副本数量不足
+var ErrQuorumNotReached = errors.New("write not acknowledged by a quorum of replicas")
+
+// ErrCommandUnsupported 表示服务端版本不支持该命令
+var ErrCommandUnsupported = errors.New("command not supported by server")
+
+// ErrReadOnlyClient 表示通过只读客户端发送了写命令
+var ErrReadOnlyClient = errors.New("write command rejected by read-only client")
+
+// ErrSingleNodeOnly 表示在单机模式下调用了仅 Cluster 模式可用的操作
+var ErrSingleNodeOnly = errors.New("operation not available in single node mode")
+
+// ErrClusterUnsupported 表示该操作不支持 Cluster 模式
+var ErrClusterUnsupported = errors.New("operation not supported in cluster mode")
+
+// ErrVersionConflict 表示乐观锁更新在重试次数内始终发生版本冲突
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrStreamIDTooSmall 表示 XSETID 指定的 ID 小于 Stream 中已有的最大条目 ID
+var ErrStreamIDTooSmall = errors.New("stream id is smaller than the stream's top item")
+
+// ErrNoExpiry 表示 key 存在但没有设置过期时间
+var ErrNoExpiry = errors.New("key has no expiry")
+
+// ErrEmptyKey 表示必填的 key 参数为空字符串
+var ErrEmptyKey = errors.New("key must not be empty")
+
+// ErrEmptyPattern 表示必填的匹配模式为空字符串
+var ErrEmptyPattern = errors.New("pattern must not be empty")
+
+// ErrWildcardPattern 表示对只由通配符组成的模式（如 "*"）执行了修改类的批量操作，且没有开启 AllowDestructiveWildcard
+var ErrWildcardPattern = errors.New("destructive operation on wildcard-only pattern requires allow_destructive_wildcard")
+
+// ErrNoInvalidationRedirect 表示开启 CLIENT TRACKING 时没有可以重定向失效消息的订阅连接
+var ErrNoInvalidationRedirect = errors.New("client tracking requires a redirect: call SubscribeInvalidations or set Redirect")
@@ -0,0 +1,134 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// eventLogField 是事件 JSON 在 Stream 条目中的字段名
+	eventLogField = "event"
+	// eventLogPageSize 是回放与跟随时每次读取的条目数
+	eventLogPageSize = 100
+	// eventLogBlock 是 Follow 每次阻塞 XREAD 的时长，到期后会检查 ctx 是否结束
+	eventLogBlock = 5 * time.Second
+)
+
+// EventLog 基于 Stream 的只追加事件日志，支持从指定 ID 回放并持续跟随新事件
+type EventLog struct {
+	stream string
+}
+
+// NewEventLog 创建事件日志，stream 为空时后续调用均返回 ErrEmptyKey
+func NewEventLog(stream string) *EventLog {
+	return &EventLog{stream: stream}
+}
+
+// Append 将 event 序列化为 JSON 后追加到日志，返回条目 ID
+func (l *EventLog) Append(ctx context.Context, event interface{}) (id string, err error) {
+	if err := checkKeys(l.stream); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal event: %v", err)
+	}
+	id, err = Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: l.stream,
+		Values: map[string]interface{}{eventLogField: data},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to append event to %s: %v", l.stream, err)
+	}
+	return id, nil
+}
+
+// Replay 按顺序回放 fromID 之后（不含 fromID）直到当前末尾的所有事件，fromID 为空或 "-" 时从头开始
+// 调用方保存最后处理的 ID，之后以它作为 fromID 即可断点续放；handler 返回错误时停止回放并返回该错误
+func (l *EventLog) Replay(ctx context.Context, fromID string, handler func(id string, event json.RawMessage) error) error {
+	_, err := l.replay(ctx, fromID, handler)
+	return err
+}
+
+// Follow 先回放 fromID 之后的历史事件，然后通过阻塞 XREAD 持续处理新事件，直到 ctx 结束或 handler 返回错误
+func (l *EventLog) Follow(ctx context.Context, fromID string, handler func(id string, event json.RawMessage) error) error {
+	lastID, err := l.replay(ctx, fromID, handler)
+	if err != nil {
+		return err
+	}
+	if lastID == "" {
+		lastID = fromID
+	}
+	if lastID == "" || lastID == "-" {
+		lastID = "0-0"
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		streams, err := Client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{l.stream, lastID},
+			Count:   eventLogPageSize,
+			Block:   eventLogBlock,
+		}).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to read events from %s: %v", l.stream, err)
+		}
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				if err := l.dispatch(msg, handler); err != nil {
+					return err
+				}
+				lastID = msg.ID
+			}
+		}
+	}
+}
+
+// replay 分页回放事件，返回最后处理的条目 ID，没有事件时返回空字符串
+func (l *EventLog) replay(ctx context.Context, fromID string, handler func(id string, event json.RawMessage) error) (string, error) {
+	if err := checkKeys(l.stream); err != nil {
+		return "", err
+	}
+	start := "-"
+	if fromID != "" && fromID != "-" {
+		start = "(" + fromID
+	}
+	lastID := ""
+	for {
+		msgs, err := Client.XRangeN(ctx, l.stream, start, "+", eventLogPageSize).Result()
+		if err != nil {
+			return lastID, fmt.Errorf("failed to replay events from %s: %v", l.stream, err)
+		}
+		for _, msg := range msgs {
+			if err := l.dispatch(msg, handler); err != nil {
+				return lastID, err
+			}
+			lastID = msg.ID
+		}
+		if len(msgs) < eventLogPageSize {
+			return lastID, nil
+		}
+		start = "(" + lastID
+	}
+}
+
+// dispatch 取出条目中的事件 JSON 并交给 handler
+func (l *EventLog) dispatch(msg redis.XMessage, handler func(id string, event json.RawMessage) error) error {
+	data, ok := msg.Values[eventLogField].(string)
+	if !ok {
+		return fmt.Errorf("entry %s of %s has no %s field", msg.ID, l.stream, eventLogField)
+	}
+	return handler(msg.ID, json.RawMessage(data))
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// FunctionLoad 加载 Redis Functions 库（Redis 7+），返回库名；replace 为 true 时替换同名库
+// Cluster 模式下会加载到所有主节点；服务端不支持时返回 ErrCommandUnsupported
+func FunctionLoad(ctx context.Context, code string, replace bool) (string, error) {
+	load := func(ctx context.Context, c redis.Cmdable) (string, error) {
+		var cmd *redis.StringCmd
+		if replace {
+			cmd = c.FunctionLoadReplace(ctx, code)
+		} else {
+			cmd = c.FunctionLoad(ctx, code)
+		}
+		name, err := cmd.Result()
+		if isUnknownCommand(err) {
+			return "", fmt.Errorf("%w: FUNCTION LOAD", ErrCommandUnsupported)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to load function library: %v", err)
+		}
+		return name, nil
+	}
+
+	var mu sync.Mutex
+	var library string
+	err := ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		name, err := load(ctx, node)
+		if err != nil {
+			if config.IsCluster {
+				return fmt.Errorf("%s: %w", node.Options().Addr, err)
+			}
+			return err
+		}
+		mu.Lock()
+		library = name
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return library, nil
+}
+
+// FCall 调用 Redis Functions 中的函数；服务端不支持时返回 ErrCommandUnsupported
+func FCall(ctx context.Context, function string, keys []string, args ...interface{}) (interface{}, error) {
+	if err := checkKeys(keys...); err != nil {
+		return nil, err
+	}
+	result, err := Client.FCall(ctx, function, keys, args...).Result()
+	return fcallResult(function, result, err)
+}
+
+// FCallRO 以只读方式调用函数（FCALL_RO），函数必须声明 no-writes 标志，可以在副本上执行
+func FCallRO(ctx context.Context, function string, keys []string, args ...interface{}) (interface{}, error) {
+	if err := checkKeys(keys...); err != nil {
+		return nil, err
+	}
+	result, err := Client.FCallRO(ctx, function, keys, args...).Result()
+	return fcallResult(function, result, err)
+}
+
+// fcallResult 统一处理 FCALL / FCALL_RO 的返回值，redis.Nil 表示函数返回 nil，不视为错误
+func fcallResult(function string, result interface{}, err error) (interface{}, error) {
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if isUnknownCommand(err) {
+		return nil, fmt.Errorf("%w: FCALL", ErrCommandUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to call function %s: %v", function, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,147 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// compactMaxRetries 是 CompactHashCounters 在哈希被并发修改时的最大重试次数
+const compactMaxRetries = 5
+
+// HRandField 随机返回哈希中的 count 个字段，不会修改哈希本身
+// count 为正数时返回不重复的字段（最多为哈希字段总数）；
+// count 为负数时返回 |count| 个字段，允许重复
+func HRandField(ctx context.Context, key string, count int) ([]string, error) {
+	if err := checkKeys(key); err != nil {
+		return nil, err
+	}
+	if config.IsCluster {
+		result, err := ClusterClient.HRandField(ctx, key, count).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get random fields of hash %s: %v", key, err)
+		}
+		return result, nil
+	} else {
+		result, err := Client.HRandField(ctx, key, count).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get random fields of hash %s: %v", key, err)
+		}
+		return result, nil
+	}
+}
+
+// HRandFieldWithValues 与 HRandField 相同，但同时返回字段对应的值
+// count 的正负语义与 HRandField 一致
+func HRandFieldWithValues(ctx context.Context, key string, count int) ([]redis.KeyValue, error) {
+	if err := checkKeys(key); err != nil {
+		return nil, err
+	}
+	if config.IsCluster {
+		result, err := ClusterClient.HRandFieldWithValues(ctx, key, count).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get random fields with values of hash %s: %v", key, err)
+		}
+		return result, nil
+	} else {
+		result, err := Client.HRandFieldWithValues(ctx, key, count).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get random fields with values of hash %s: %v", key, err)
+		}
+		return result, nil
+	}
+}
+
+// CompactHashCounters 将按时间分桶的计数器哈希中的旧字段合并为更粗粒度的字段
+// bucketParser 判断字段是否为时间桶并解析出对应时间；时间早于当前整点的桶视为旧桶，
+// 所有旧桶（字段名 -> 计数）会被交给 rollup，rollup 返回的字段会替换掉这些旧桶。
+// 由于旧桶全部被替换，rollup 产出的字段如果再次被 bucketParser 识别为旧桶，下次压缩时会连同新的旧桶一起传入 rollup，
+// 因此 rollup 需要对同名字段做累加。整个读-改-写过程通过 WATCH/MULTI 保证原子性，哈希被并发修改时自动重试
+func CompactHashCounters(ctx context.Context, key string, bucketParser func(field string) (time.Time, bool), rollup func(fields map[string]int64) map[string]int64) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	cutoff := time.Now().Truncate(time.Hour)
+	compact := func(tx *redis.Tx) error {
+		all, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		old := make(map[string]int64)
+		for field, value := range all {
+			t, ok := bucketParser(field)
+			if !ok || !t.Before(cutoff) {
+				continue
+			}
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("field %s has non-integer value %q", field, value)
+			}
+			old[field] = n
+		}
+		if len(old) == 0 {
+			return nil
+		}
+		compacted := rollup(old)
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			fields := make([]string, 0, len(old))
+			for field := range old {
+				fields = append(fields, field)
+			}
+			pipe.HDel(ctx, key, fields...)
+			if len(compacted) > 0 {
+				values := make([]interface{}, 0, len(compacted)*2)
+				for field, n := range compacted {
+					values = append(values, field, n)
+				}
+				pipe.HSet(ctx, key, values...)
+			}
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < compactMaxRetries; i++ {
+		err := Client.Watch(ctx, compact, key)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, redis.TxFailedErr) {
+			return fmt.Errorf("failed to compact counters of hash %s: %v", key, err)
+		}
+	}
+	return fmt.Errorf("failed to compact counters of hash %s: %w", key, redis.TxFailedErr)
+}
+
+// HMGetMany 对多个哈希读取相同的字段，返回 key -> 按 fields 顺序排列的字段值
+// 不存在的哈希或字段对应的值为 nil；命令按哈希槽分组 pipeline 执行
+func HMGetMany(ctx context.Context, keys []string, fields ...string) (map[string][]interface{}, error) {
+	result := make(map[string][]interface{}, len(keys))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("HMGET requires at least one field")
+	}
+	if err := checkKeys(keys...); err != nil {
+		return nil, err
+	}
+	for _, group := range groupKeysBySlot(keys) {
+		cmds := make([]*redis.SliceCmd, len(group))
+		_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, key := range group {
+				cmds[i] = pipe.HMGet(ctx, key, fields...)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get fields of hashes: %v", err)
+		}
+		for i, key := range group {
+			result[key] = cmds[i].Val()
+		}
+	}
+	return result, nil
+}
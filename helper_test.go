@@ -0,0 +1,29 @@
+package redis
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+// setupTestRedis 连接 REDIS_ADDR（默认 localhost:6379）上的单节点 Redis，连接失败时跳过测试
+func setupTestRedis(t *testing.T) context.Context {
+	t.Helper()
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	ctx := context.Background()
+	if err := InitRedisClientWithConfig(ctx, RedisConfig{Addr: addr}); err != nil {
+		t.Skipf("redis not available at %s: %v", addr, err)
+	}
+	t.Cleanup(func() { Client.Close() })
+	return ctx
+}
+
+// cleanupKeys 在测试结束时删除 keys
+func cleanupKeys(t *testing.T, ctx context.Context, keys ...string) {
+	t.Helper()
+	Client.Del(ctx, keys...)
+	t.Cleanup(func() { Client.Del(context.Background(), keys...) })
+}
@@ -0,0 +1,199 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// hotKeyMaxLen 是记录的 key 的最大长度，更长的 key 会被截断，避免超长 key 占用过多内存
+	hotKeyMaxLen = 128
+	// hotKeyMaxTracked 是每个子窗口最多跟踪的不同 key 数量，超出后新出现的 key 不再记录
+	hotKeyMaxTracked = 10000
+	// hotKeyBuckets 是滑动窗口划分的子窗口数量
+	hotKeyBuckets = 10
+	// hotKeyMinWindow 是允许的最短窗口，保证每个子窗口至少 1ms
+	hotKeyMinWindow = hotKeyBuckets * time.Millisecond
+)
+
+// HotKey 是 HotKeys 返回的热点 key 统计
+type HotKey struct {
+	Key   string  // 截断后的 key
+	Count int64   // 窗口内的估算访问次数（采样次数 / 采样率）
+	Rate  float64 // 窗口内的估算每秒访问次数
+}
+
+// hotKeys 是当前的热点 key 检测器，为 nil 时不做任何记录
+var hotKeys atomic.Pointer[hotKeyDetector]
+
+// EnableHotKeyDetection 开启客户端热点 key 检测：按 sampleRate（0~1]的比例采样经过 Client 的命令，
+// 在长度为 window 的滑动窗口内统计每个 key 的访问次数，通过 HotKeys 查询。
+// 通过 COMMAND 获取每个命令的第一个 key 的位置，不带 key 的命令不会被记录。重复调用会重置统计
+func EnableHotKeyDetection(ctx context.Context, sampleRate float64, window time.Duration) error {
+	if sampleRate <= 0 || sampleRate > 1 {
+		return fmt.Errorf("invalid sample rate %v: must be in (0, 1]", sampleRate)
+	}
+	if window < hotKeyMinWindow {
+		return fmt.Errorf("invalid hot key window %v: must be at least %v", window, hotKeyMinWindow)
+	}
+	commands, err := Client.Command(ctx).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get command info: %v", err)
+	}
+	firstKey := make(map[string]int, len(commands))
+	for name, info := range commands {
+		if info.FirstKeyPos > 0 {
+			firstKey[name] = int(info.FirstKeyPos)
+		}
+	}
+
+	d := &hotKeyDetector{
+		sampleRate:   sampleRate,
+		window:       window,
+		firstKey:     firstKey,
+		buckets:      make([]map[string]int64, hotKeyBuckets),
+		currentStart: time.Now(),
+	}
+	for i := range d.buckets {
+		d.buckets[i] = make(map[string]int64)
+	}
+	hotKeys.Store(d)
+	return nil
+}
+
+// DisableHotKeyDetection 关闭热点 key 检测并丢弃统计
+func DisableHotKeyDetection() {
+	hotKeys.Store(nil)
+}
+
+// HotKeys 返回滑动窗口内访问次数最多的 topN 个 key，未开启检测时返回 nil
+func HotKeys(topN int) []HotKey {
+	d := hotKeys.Load()
+	if d == nil {
+		return nil
+	}
+	return d.top(topN)
+}
+
+type hotKeyDetector struct {
+	sampleRate float64
+	window     time.Duration
+	firstKey   map[string]int // 命令名 -> 第一个 key 在参数中的位置
+
+	mu           sync.Mutex
+	buckets      []map[string]int64
+	current      int
+	currentStart time.Time
+}
+
+// record 按采样率记录命令访问的 key
+func (d *hotKeyDetector) record(cmd redis.Cmder) {
+	pos, ok := d.firstKey[cmd.Name()]
+	if !ok || rand.Float64() >= d.sampleRate {
+		return
+	}
+	args := cmd.Args()
+	if pos >= len(args) {
+		return
+	}
+	key, ok := args[pos].(string)
+	if !ok {
+		return
+	}
+	if len(key) > hotKeyMaxLen {
+		key = key[:hotKeyMaxLen]
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rotate(time.Now())
+	bucket := d.buckets[d.current]
+	if _, ok := bucket[key]; !ok && len(bucket) >= hotKeyMaxTracked {
+		return
+	}
+	bucket[key]++
+}
+
+// rotate 将过期的子窗口清空，调用方需持有 d.mu
+func (d *hotKeyDetector) rotate(now time.Time) {
+	size := d.window / hotKeyBuckets
+	if now.Sub(d.currentStart) >= d.window+size {
+		for i := range d.buckets {
+			d.buckets[i] = make(map[string]int64)
+		}
+		d.currentStart = now
+		return
+	}
+	for now.Sub(d.currentStart) >= size {
+		d.current = (d.current + 1) % len(d.buckets)
+		d.buckets[d.current] = make(map[string]int64)
+		d.currentStart = d.currentStart.Add(size)
+	}
+}
+
+// top 汇总所有子窗口并返回访问次数最多的 topN 个 key
+func (d *hotKeyDetector) top(topN int) []HotKey {
+	d.mu.Lock()
+	d.rotate(time.Now())
+	counts := make(map[string]int64)
+	for _, bucket := range d.buckets {
+		for key, n := range bucket {
+			counts[key] += n
+		}
+	}
+	d.mu.Unlock()
+
+	result := make([]HotKey, 0, len(counts))
+	for key, n := range counts {
+		estimated := int64(float64(n) / d.sampleRate)
+		result = append(result, HotKey{Key: key, Count: estimated, Rate: float64(estimated) / d.window.Seconds()})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return strings.Compare(result[i].Key, result[j].Key) < 0
+	})
+	if topN > 0 && len(result) > topN {
+		result = result[:topN]
+	}
+	return result
+}
+
+// hotKeyHook 在开启热点 key 检测时记录经过客户端的命令
+type hotKeyHook struct{}
+
+func (hotKeyHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (hotKeyHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if d := hotKeys.Load(); d != nil {
+			d.record(cmd)
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (hotKeyHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if d := hotKeys.Load(); d != nil {
+			for _, cmd := range cmds {
+				d.record(cmd)
+			}
+		}
+		return next(ctx, cmds)
+	}
+}
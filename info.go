@@ -0,0 +1,32 @@
+package redis
+
+import (
+	"strconv"
+	"strings"
+)
+
+// parseInfo 将 INFO 命令的输出解析为 field -> value 的映射，忽略注释行与空行
+func parseInfo(info string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields[k] = v
+	}
+	return fields
+}
+
+// infoInt 读取数值型的 INFO 字段，字段缺失或格式不正确时返回 0
+func infoInt(fields map[string]string, name string) int64 {
+	n, err := strconv.ParseInt(fields[name], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"context"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ScanIterator 以迭代器的方式惰性扫描匹配的 key，用法与 sql.Rows / bufio.Scanner 相同：
+//
+//	it := NewScanIterator(ctx, "user:*", 100)
+//	for it.Next() {
+//		use(it.Key())
+//	}
+//	if err := it.Err(); err != nil { ... }
+//
+// Cluster 模式下依次扫描每个主节点，每次只从服务端取一批 key。ScanIterator 不是并发安全的
+type ScanIterator struct {
+	ctx     context.Context
+	pattern string
+	count   int64
+
+	nodes   []*redis.Client // 待扫描的主节点，单机模式下只有 Client
+	current *redis.ScanIterator
+	started bool
+	err     error
+}
+
+// NewScanIterator 创建扫描迭代器，扫描在第一次调用 Next 时才开始
+func NewScanIterator(ctx context.Context, pattern string, count int64) *ScanIterator {
+	return &ScanIterator{ctx: ctx, pattern: pattern, count: count, err: checkPattern(pattern)}
+}
+
+// Next 前进到下一个 key，没有更多 key 或出错时返回 false
+func (it *ScanIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		if err := it.start(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	for it.current != nil {
+		if it.current.Next(it.ctx) {
+			return true
+		}
+		if err := it.current.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		it.current = it.nextNode()
+	}
+	return false
+}
+
+// Key 返回当前的 key
+func (it *ScanIterator) Key() string {
+	if it.current == nil {
+		return ""
+	}
+	return it.current.Val()
+}
+
+// Err 返回迭代过程中遇到的错误
+func (it *ScanIterator) Err() error {
+	return it.err
+}
+
+// start 准备扫描：通过 ForEachMaster 获取所有主节点（单机模式下为 Client），然后从第一个节点开始扫描
+func (it *ScanIterator) start() error {
+	var mu sync.Mutex
+	err := ForEachMaster(it.ctx, func(ctx context.Context, node *redis.Client) error {
+		mu.Lock()
+		it.nodes = append(it.nodes, node)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	it.current = it.nextNode()
+	return nil
+}
+
+// nextNode 返回下一个主节点的扫描迭代器，没有更多节点时返回 nil
+func (it *ScanIterator) nextNode() *redis.ScanIterator {
+	if len(it.nodes) == 0 {
+		return nil
+	}
+	node := it.nodes[0]
+	it.nodes = it.nodes[1:]
+	return node.Scan(it.ctx, 0, it.pattern, it.count).Iterator()
+}
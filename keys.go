@@ -0,0 +1,247 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// FindOrphans 扫描匹配 childPattern 的子 key，通过 parentKeyFor 计算其父 key，
+// 返回父 key 已不存在的子 key。父 key 的 EXISTS 检查按哈希槽分批 pipeline 执行，
+// 扫描过程中被删除的子 key 会被跳过
+func FindOrphans(ctx context.Context, childPattern string, parentKeyFor func(childKey string) string) ([]string, error) {
+	var mu sync.Mutex
+	var orphans []string
+
+	err := Scan(ctx, childPattern, 100, func(children []string) error {
+		parentOf := make(map[string]string, len(children))
+		parents := make([]string, 0, len(children))
+		for _, child := range children {
+			parent := parentKeyFor(child)
+			parentOf[child] = parent
+			parents = append(parents, parent)
+		}
+
+		parentExists, err := existsBySlot(ctx, parents)
+		if err != nil {
+			return err
+		}
+		var candidates []string
+		for child, parent := range parentOf {
+			if !parentExists[parent] {
+				candidates = append(candidates, child)
+			}
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		childExists, err := existsBySlot(ctx, candidates)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		for _, child := range candidates {
+			if childExists[child] {
+				orphans = append(orphans, child)
+			}
+		}
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orphans, nil
+}
+
+// existsBySlot 按哈希槽分组 pipeline 执行 EXISTS，返回每个 key 是否存在
+func existsBySlot(ctx context.Context, keys []string) (map[string]bool, error) {
+	exists := make(map[string]bool, len(keys))
+	for _, group := range groupKeysBySlot(keys) {
+		cmds := make([]*redis.IntCmd, len(group))
+		_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, key := range group {
+				cmds[i] = pipe.Exists(ctx, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existence of keys: %v", err)
+		}
+		for i, key := range group {
+			exists[key] = cmds[i].Val() > 0
+		}
+	}
+	return exists, nil
+}
+
+// Rename 将 oldKey 重命名为 newKey（已存在的 newKey 会被覆盖），TTL 保持不变
+// Cluster 模式下两个 key 不在同一个哈希槽时，回退为 DUMP + RESTORE（带原 TTL）+ DEL，该回退不是原子的
+// oldKey 不存在时返回 ErrKeyNotFound
+func Rename(ctx context.Context, oldKey, newKey string) error {
+	if err := checkKeys(oldKey, newKey); err != nil {
+		return err
+	}
+	if same, _ := SameSlot(oldKey, newKey); !config.IsCluster || same {
+		err := Client.Rename(ctx, oldKey, newKey).Err()
+		if err != nil && strings.Contains(err.Error(), "no such key") {
+			return fmt.Errorf("%w: %s", ErrKeyNotFound, oldKey)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to rename key %s to %s: %v", oldKey, newKey, err)
+		}
+		invalidateLocal(oldKey, newKey)
+		return nil
+	}
+
+	dump, err := Client.Dump(ctx, oldKey).Result()
+	if err == redis.Nil {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, oldKey)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to dump key %s: %v", oldKey, err)
+	}
+	ttl, err := Client.PTTL(ctx, oldKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get ttl of key %s: %v", oldKey, err)
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+	if err := Client.RestoreReplace(ctx, newKey, ttl, dump).Err(); err != nil {
+		return fmt.Errorf("failed to restore key %s: %v", newKey, err)
+	}
+	if err := Client.Del(ctx, oldKey).Err(); err != nil {
+		return fmt.Errorf("failed to delete key %s after rename: %v", oldKey, err)
+	}
+	invalidateLocal(oldKey, newKey)
+	return nil
+}
+
+// RenameByPattern 扫描匹配 pattern 的 key，通过 transform 计算新 key 名并重命名（使用 Rename，TTL 保持不变），返回重命名的数量
+// transform 返回 skip 为 true 或新旧 key 相同时跳过该 key；dryRun 为 true 时只统计将被重命名的数量，不做任何修改
+// 已经重命名的 key 在重新执行时应由 transform 跳过，因此中断后可以直接重新执行；扫描期间被删除的 key 会被跳过
+func RenameByPattern(ctx context.Context, pattern string, transform func(oldKey string) (newKey string, skip bool), dryRun bool) (int, error) {
+	if !dryRun {
+		if err := checkDestructivePattern(pattern); err != nil {
+			return 0, err
+		}
+	}
+	var mu sync.Mutex
+	renamed := 0
+	err := Scan(ctx, pattern, 100, func(keys []string) error {
+		for _, oldKey := range keys {
+			newKey, skip := transform(oldKey)
+			if skip || newKey == oldKey {
+				continue
+			}
+			if !dryRun {
+				err := Rename(ctx, oldKey, newKey)
+				if errors.Is(err, ErrKeyNotFound) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+			}
+			mu.Lock()
+			renamed++
+			mu.Unlock()
+		}
+		return nil
+	})
+	return renamed, err
+}
+
+// ScanProcess 分批扫描匹配 pattern 的 key，对每批 key 打开一个 pipeline 交给 process 排入任意命令后执行，返回处理的 key 总数
+// Cluster 模式下每批 key 会先按哈希槽分组，每组单独调用一次 process 并执行 pipeline
+// process 只负责排入命令，命令的执行结果需要调用方自行保存 Cmd 后读取；命令返回 redis.Nil 不视为错误
+// process 通常用于删除或修改 key，因此只由通配符组成的 pattern 需要开启 AllowDestructiveWildcard
+func ScanProcess(ctx context.Context, pattern string, count int64, process func(pipe redis.Pipeliner, keys []string)) (processed int64, err error) {
+	if err := checkDestructivePattern(pattern); err != nil {
+		return 0, err
+	}
+	var mu sync.Mutex
+	err = Scan(ctx, pattern, count, func(keys []string) error {
+		for _, group := range groupKeysBySlot(keys) {
+			cmds, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+				process(pipe, group)
+				return nil
+			})
+			if err != nil && err != redis.Nil {
+				return fmt.Errorf("failed to process keys: %v", err)
+			}
+			// Pipelined 只返回第一个错误，前面的 redis.Nil 会掩盖后面真正的错误，需要逐条检查
+			for _, cmd := range cmds {
+				if err := cmd.Err(); err != nil && err != redis.Nil {
+					return fmt.Errorf("failed to process keys: %v", err)
+				}
+			}
+			// process 通常会修改或删除这些 key，本地降级缓存中的旧值不再可信
+			invalidateLocal(group...)
+			mu.Lock()
+			processed += int64(len(group))
+			mu.Unlock()
+		}
+		return nil
+	})
+	return processed, err
+}
+
+// CountKeys 返回匹配 pattern 的 key 数量，Cluster 模式下汇总所有主节点
+// 通过 Scan 逐批计数，不会在内存中保存 key；与 SCAN 一样需要遍历整个 keyspace（O(N)），开销较大，但不会像 KEYS 那样阻塞服务端
+// 扫描期间新增或删除的 key 可能被计入也可能不计入，rehash 时 SCAN 可能重复返回同一个 key，因此结果是近似值
+func CountKeys(ctx context.Context, pattern string) (int64, error) {
+	var mu sync.Mutex
+	var count int64
+	err := Scan(ctx, pattern, 1000, func(keys []string) error {
+		mu.Lock()
+		count += int64(len(keys))
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// patternStatsMaxGroups 是 PatternStats 最多跟踪的前缀分组数，超出的 key 计入 "other"
+const patternStatsMaxGroups = 10000
+
+// PatternStats 扫描所有 key，按前 maxSegments 段（以 delimiter 分隔）的前缀分组计数，如 "session:*" -> 10000000
+// 段数不超过 maxSegments 的 key 以自身作为分组。最多跟踪 patternStatsMaxGroups 个分组，之后出现的新分组统一计入 "other"，
+// 因此内存占用有上限。与 SCAN 一样需要遍历整个 key 空间
+func PatternStats(ctx context.Context, delimiter string, maxSegments int) (map[string]int64, error) {
+	if delimiter == "" || maxSegments < 1 {
+		return nil, fmt.Errorf("invalid pattern stats arguments: delimiter %q, max segments %d", delimiter, maxSegments)
+	}
+
+	var mu sync.Mutex
+	stats := make(map[string]int64)
+	err := Scan(ctx, "*", 1000, func(keys []string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, key := range keys {
+			group := key
+			segments := strings.SplitN(key, delimiter, maxSegments+1)
+			if len(segments) > maxSegments {
+				group = strings.Join(segments[:maxSegments], delimiter) + delimiter + "*"
+			}
+			if _, ok := stats[group]; !ok && len(stats) >= patternStatsMaxGroups {
+				group = "other"
+			}
+			stats[group]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
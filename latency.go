@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LatencyEvent 是 LATENCY LATEST 返回的一条事件记录
+type LatencyEvent struct {
+	Node      string // 节点地址
+	Event     string // 事件名，如 command、fork、expire-cycle
+	Timestamp time.Time
+	Latest    time.Duration
+	Max       time.Duration
+}
+
+// LatencySample 是 LATENCY HISTORY 返回的一个采样点
+type LatencySample struct {
+	Node      string
+	Timestamp time.Time
+	Latency   time.Duration
+}
+
+// LatencyLatest 返回各事件最近一次的延迟记录，Cluster 模式下包含所有主节点的结果
+func LatencyLatest(ctx context.Context) ([]LatencyEvent, error) {
+	var mu sync.Mutex
+	var events []LatencyEvent
+	err := forEachNode(ctx, func(ctx context.Context, addr string, c redis.UniversalClient) error {
+		reply, err := c.Do(ctx, "latency", "latest").Slice()
+		if err != nil {
+			return fmt.Errorf("failed to get latest latency of %s: %v", addr, err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, item := range reply {
+			fields, ok := item.([]interface{})
+			if !ok || len(fields) < 4 {
+				continue
+			}
+			name, _ := fields[0].(string)
+			events = append(events, LatencyEvent{
+				Node:      addr,
+				Event:     name,
+				Timestamp: time.Unix(replyInt(fields[1]), 0),
+				Latest:    time.Duration(replyInt(fields[2])) * time.Millisecond,
+				Max:       time.Duration(replyInt(fields[3])) * time.Millisecond,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// LatencyHistory 返回指定事件的延迟历史，Cluster 模式下包含所有主节点的结果
+func LatencyHistory(ctx context.Context, event string) ([]LatencySample, error) {
+	var mu sync.Mutex
+	var samples []LatencySample
+	err := forEachNode(ctx, func(ctx context.Context, addr string, c redis.UniversalClient) error {
+		reply, err := c.Do(ctx, "latency", "history", event).Slice()
+		if err != nil {
+			return fmt.Errorf("failed to get latency history of %s on %s: %v", event, addr, err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, item := range reply {
+			fields, ok := item.([]interface{})
+			if !ok || len(fields) < 2 {
+				continue
+			}
+			samples = append(samples, LatencySample{
+				Node:      addr,
+				Timestamp: time.Unix(replyInt(fields[0]), 0),
+				Latency:   time.Duration(replyInt(fields[1])) * time.Millisecond,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// LatencyReset 清空所有事件的延迟记录，返回被清空的事件数量（Cluster 模式下为所有主节点之和）
+func LatencyReset(ctx context.Context) (int64, error) {
+	var mu sync.Mutex
+	var total int64
+	err := forEachNode(ctx, func(ctx context.Context, addr string, c redis.UniversalClient) error {
+		n, err := c.Do(ctx, "latency", "reset").Int64()
+		if err != nil {
+			return fmt.Errorf("failed to reset latency of %s: %v", addr, err)
+		}
+		mu.Lock()
+		total += n
+		mu.Unlock()
+		return nil
+	})
+	return total, err
+}
+
+// forEachNode 在单机模式下对 Client 执行 fn，Cluster 模式下对每个主节点执行 fn
+func forEachNode(ctx context.Context, fn func(ctx context.Context, addr string, c redis.UniversalClient) error) error {
+	return ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		return fn(ctx, node.Options().Addr, node)
+	})
+}
+
+// replyInt 将回复中的整数元素转换为 int64，类型不符时返回 0
+func replyInt(v interface{}) int64 {
+	n, _ := v.(int64)
+	return n
+}
+
+// LatencyDoctor 返回 LATENCY DOCTOR 的诊断文本，以节点地址为 key；Cluster 模式下包含所有主节点
+func LatencyDoctor(ctx context.Context) (map[string]string, error) {
+	return doctor(ctx, "latency")
+}
+
+// MemoryDoctor 返回 MEMORY DOCTOR 的诊断文本，以节点地址为 key；Cluster 模式下包含所有主节点
+func MemoryDoctor(ctx context.Context) (map[string]string, error) {
+	return doctor(ctx, "memory")
+}
+
+// doctor 在每个节点上执行 "<command> DOCTOR" 并收集输出
+func doctor(ctx context.Context, command string) (map[string]string, error) {
+	var mu sync.Mutex
+	reports := make(map[string]string)
+	err := forEachNode(ctx, func(ctx context.Context, addr string, c redis.UniversalClient) error {
+		report, err := c.Do(ctx, command, "doctor").Text()
+		if err != nil {
+			return fmt.Errorf("failed to get %s doctor report of %s: %v", command, addr, err)
+		}
+		mu.Lock()
+		reports[addr] = report
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LMPop 从 keys 中第一个非空的列表弹出最多 count 个元素，direction 为 "left" 或 "right"
+// 返回弹出元素所在的 key；所有列表都为空时返回空 key 和 nil
+// Cluster 模式下所有 key 必须位于同一个哈希槽；不支持 LMPOP 的旧版本 Redis 会依次尝试每个 key 的 LPOP/RPOP
+func LMPop(ctx context.Context, direction string, count int64, keys ...string) (key string, values []string, err error) {
+	direction = strings.ToUpper(direction)
+	if direction != "LEFT" && direction != "RIGHT" {
+		return "", nil, fmt.Errorf("invalid direction %q: must be left or right", direction)
+	}
+	if len(keys) == 0 {
+		return "", nil, fmt.Errorf("LMPOP requires at least one key")
+	}
+	if err := checkKeys(keys...); err != nil {
+		return "", nil, err
+	}
+	if err := checkSameSlot(keys...); err != nil {
+		return "", nil, err
+	}
+
+	cmd := withFirstKey(redis.NewKeyValuesCmd(ctx, numKeysArgs("lmpop", keys, direction, "count", count)...))
+	_ = Client.Process(ctx, cmd)
+	key, values, err = cmd.Result()
+	if err == redis.Nil {
+		return "", nil, nil
+	}
+	if err == nil {
+		return key, values, nil
+	}
+	if !isUnknownCommand(err) {
+		return "", nil, fmt.Errorf("failed to pop from lists %v: %v", keys, err)
+	}
+
+	for _, key := range keys {
+		var cmd *redis.StringSliceCmd
+		if direction == "LEFT" {
+			cmd = Client.LPopCount(ctx, key, int(count))
+		} else {
+			cmd = Client.RPopCount(ctx, key, int(count))
+		}
+		values, err := cmd.Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to pop from list %s: %v", key, err)
+		}
+		if len(values) > 0 {
+			return key, values, nil
+		}
+	}
+	return "", nil, nil
+}
@@ -0,0 +1,134 @@
+package redis
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultLocalFallbackSize 是未配置 LocalFallbackSize 时本地降级缓存的容量
+const defaultLocalFallbackSize = 10000
+
+// localFallback 是本地降级缓存，EnableLocalFallback 为 false 时为 nil
+//
+// 开启后 Set 成功写入 Redis 的字符串值会同步写入本地缓存，通过本包其他写操作修改的 key 会从本地缓存中删除；
+// 当 Get 因连接错误（而非 key 不存在等服务端返回的错误）失败时，返回本地缓存中的值。
+// 一致性取舍：本地缓存只包含本进程写入的值，其他进程或直接通过 Client 的写入不会使其失效，
+// 因此 Redis 故障期间读到的可能是旧数据；TTL 只在读取时近似检查，容量满时按 LRU 淘汰
+var localFallback *localCache
+
+// localCache 是带 TTL 的定长 LRU 缓存
+type localCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type localEntry struct {
+	key      string
+	value    string
+	expireAt time.Time // 零值表示不过期
+}
+
+func newLocalCache(size int) *localCache {
+	if size <= 0 {
+		size = defaultLocalFallbackSize
+	}
+	return &localCache{size: size, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+// Get 返回未过期的缓存值
+func (c *localCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+	entry := elem.Value.(*localEntry)
+	if !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		c.ll.Remove(elem)
+		delete(c.items, key)
+		return "", false
+	}
+	c.ll.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set 写入缓存，ttl 小于等于 0 表示不过期
+func (c *localCache) Set(key, value string, ttl time.Duration) {
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		elem.Value = &localEntry{key: key, value: value, expireAt: expireAt}
+		c.ll.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&localEntry{key: key, value: value, expireAt: expireAt})
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*localEntry).key)
+	}
+}
+
+// Delete 删除缓存
+func (c *localCache) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if elem, ok := c.items[key]; ok {
+			c.ll.Remove(elem)
+			delete(c.items, key)
+		}
+	}
+}
+
+// localFallbackSet 在开启本地降级缓存时写入字符串值，其他类型的值只做失效处理
+func localFallbackSet(key string, value interface{}, ttl time.Duration) {
+	if localFallback == nil {
+		return
+	}
+	switch v := value.(type) {
+	case string:
+		localFallback.Set(key, v, ttl)
+	case []byte:
+		localFallback.Set(key, string(v), ttl)
+	default:
+		localFallback.Delete(key)
+	}
+}
+
+// invalidateLocal 在开启本地降级缓存时删除被修改的 key
+func invalidateLocal(keys ...string) {
+	if localFallback != nil {
+		localFallback.Delete(keys...)
+	}
+}
+
+// localFallbackGet 在 err 为连接错误时尝试从本地降级缓存读取；调用方的 ctx 已经结束时不降级，直接返回错误
+func localFallbackGet(ctx context.Context, key string, err error) (string, bool) {
+	if localFallback == nil || ctx.Err() != nil || !isConnError(err) {
+		return "", false
+	}
+	return localFallback.Get(key)
+}
+
+// isConnError 判断错误是否为连接层面的错误，redis.Nil、服务端返回的错误和 ctx 被取消都不算
+// context.DeadlineExceeded 仍视为连接错误：调用方 ctx 未结束时它来自 DefaultCommandTimeout 等内部超时
+func isConnError(err error) bool {
+	if err == nil || err == redis.Nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var redisErr redis.Error
+	return !errors.As(err, &redisErr)
+}
@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// luaNamePattern 限制 LuaBuilder 中 key / 参数的名字为合法的 Lua 标识符
+var luaNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LuaBuilder 通过具名的 key 和参数构造 Lua 脚本，自动生成 local k_<name> = KEYS[i] / local a_<name> = ARGV[j] 前导代码，
+// 避免手写 KEYS/ARGV 下标时出现错位
+//
+//	script, err := NewLuaBuilder().
+//		Key("counter").
+//		Arg("limit").
+//		Body(`if tonumber(redis.call('GET', k_counter) or '0') < tonumber(a_limit) then return redis.call('INCR', k_counter) end return -1`).
+//		Build()
+//	result, err := script.Run(ctx, map[string]string{"counter": "c:1"}, map[string]interface{}{"limit": 10})
+type LuaBuilder struct {
+	keys []string
+	args []string
+	body string
+}
+
+// LuaScript 是 LuaBuilder 构造出的脚本
+type LuaScript struct {
+	Source string // 包含前导代码的完整脚本
+	keys   []string
+	args   []string
+	script *redis.Script
+}
+
+// NewLuaBuilder 创建空的 LuaBuilder
+func NewLuaBuilder() *LuaBuilder {
+	return &LuaBuilder{}
+}
+
+// Key 声明一个 key，脚本中以 k_<name> 引用，按声明顺序对应 KEYS[1..n]
+func (b *LuaBuilder) Key(name string) *LuaBuilder {
+	b.keys = append(b.keys, name)
+	return b
+}
+
+// Arg 声明一个参数，脚本中以 a_<name> 引用，按声明顺序对应 ARGV[1..n]
+func (b *LuaBuilder) Arg(name string) *LuaBuilder {
+	b.args = append(b.args, name)
+	return b
+}
+
+// Body 设置脚本主体
+func (b *LuaBuilder) Body(body string) *LuaBuilder {
+	b.body = body
+	return b
+}
+
+// Build 校验声明的名字并生成脚本，名字不是合法的 Lua 标识符或重复声明时返回错误
+func (b *LuaBuilder) Build() (*LuaScript, error) {
+	seen := make(map[string]bool)
+	var source strings.Builder
+	for i, name := range b.keys {
+		if err := checkLuaName("key", name, seen); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&source, "local k_%s = KEYS[%d]\n", name, i+1)
+	}
+	for i, name := range b.args {
+		if err := checkLuaName("arg", name, seen); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&source, "local a_%s = ARGV[%d]\n", name, i+1)
+	}
+	source.WriteString(b.body)
+
+	return &LuaScript{
+		Source: source.String(),
+		keys:   append([]string(nil), b.keys...),
+		args:   append([]string(nil), b.args...),
+		script: redis.NewScript(source.String()),
+	}, nil
+}
+
+// checkLuaName 校验名字合法且未被声明过
+func checkLuaName(kind, name string, seen map[string]bool) error {
+	if !luaNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid lua %s name %q", kind, name)
+	}
+	if seen[kind+":"+name] {
+		return fmt.Errorf("duplicate lua %s name %q", kind, name)
+	}
+	seen[kind+":"+name] = true
+	return nil
+}
+
+// Bind 按声明顺序将具名的 key 和参数转换为传给 EVAL/EVALSHA 的 keys 与 args
+// 缺少已声明的名字或传入未声明的名字时返回错误
+func (s *LuaScript) Bind(keys map[string]string, args map[string]interface{}) ([]string, []interface{}, error) {
+	if len(keys) != len(s.keys) || len(args) != len(s.args) {
+		return nil, nil, fmt.Errorf("lua script expects keys %v and args %v, got %d keys and %d args", s.keys, s.args, len(keys), len(args))
+	}
+	orderedKeys := make([]string, len(s.keys))
+	for i, name := range s.keys {
+		key, ok := keys[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("missing lua key %q", name)
+		}
+		orderedKeys[i] = key
+	}
+	orderedArgs := make([]interface{}, len(s.args))
+	for i, name := range s.args {
+		arg, ok := args[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("missing lua arg %q", name)
+		}
+		orderedArgs[i] = arg
+	}
+	return orderedKeys, orderedArgs, nil
+}
+
+// Run 绑定 key 与参数后执行脚本（优先 EVALSHA，脚本未缓存时回退为 EVAL），脚本返回 nil 时结果为 nil
+// Cluster 模式下所有 key 必须位于同一个哈希槽
+func (s *LuaScript) Run(ctx context.Context, keys map[string]string, args map[string]interface{}) (interface{}, error) {
+	orderedKeys, orderedArgs, err := s.Bind(keys, args)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKeys(orderedKeys...); err != nil {
+		return nil, err
+	}
+	if err := checkSameSlot(orderedKeys...); err != nil {
+		return nil, err
+	}
+	result, err := s.script.Run(ctx, Client, orderedKeys, orderedArgs...).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to run lua script: %v", err)
+	}
+	return result, nil
+}
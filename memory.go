@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// memorySampleConcurrency 是并发执行 MEMORY USAGE 的最大数量
+const memorySampleConcurrency = 16
+
+// NodeMemoryStatus 单个节点的内存与淘汰状态
+type NodeMemoryStatus struct {
+	Addr            string
+	UsedMemory      int64
+	MaxMemory       int64 // 0 表示未设置 maxmemory
+	MaxMemoryPolicy string
+	EvictedKeys     int64
+	Utilization     float64 // 内存使用率百分比，MaxMemory 为 0 时为 0
+}
+
+// MemoryStatusReport 内存状态报告，单机模式下 Nodes 只有一个元素
+type MemoryStatusReport struct {
+	Nodes     []NodeMemoryStatus
+	Aggregate NodeMemoryStatus // 所有节点的汇总，Addr 为空
+	Hottest   string           // 内存使用率最高的节点地址
+}
+
+// MemoryStatus 读取 used_memory、maxmemory、maxmemory_policy 和 evicted_keys 并计算内存使用率
+// Cluster 模式下返回每个主节点的状态以及汇总值
+func MemoryStatus(ctx context.Context) (*MemoryStatusReport, error) {
+	report := &MemoryStatusReport{}
+	var mu sync.Mutex
+	err := ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		status, err := nodeMemoryStatus(ctx, node, node.Options().Addr)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		report.Nodes = append(report.Nodes, *status)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var hottest float64 = -1
+	for _, node := range report.Nodes {
+		report.Aggregate.UsedMemory += node.UsedMemory
+		report.Aggregate.MaxMemory += node.MaxMemory
+		report.Aggregate.EvictedKeys += node.EvictedKeys
+		if node.Utilization > hottest {
+			hottest = node.Utilization
+			report.Hottest = node.Addr
+		}
+		if report.Aggregate.MaxMemoryPolicy == "" {
+			report.Aggregate.MaxMemoryPolicy = node.MaxMemoryPolicy
+		} else if report.Aggregate.MaxMemoryPolicy != node.MaxMemoryPolicy {
+			report.Aggregate.MaxMemoryPolicy = "mixed"
+		}
+	}
+	report.Aggregate.Utilization = utilization(report.Aggregate.UsedMemory, report.Aggregate.MaxMemory)
+	return report, nil
+}
+
+// nodeMemoryStatus 从单个节点的 INFO memory / INFO stats 中读取内存状态
+// 旧版本 INFO 中没有 maxmemory 字段时回退到 CONFIG GET
+func nodeMemoryStatus(ctx context.Context, c redis.Cmdable, addr string) (*NodeMemoryStatus, error) {
+	memInfo, err := c.Info(ctx, "memory").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memory info of %s: %v", addr, err)
+	}
+	statsInfo, err := c.Info(ctx, "stats").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats info of %s: %v", addr, err)
+	}
+	mem := parseInfo(memInfo)
+	stats := parseInfo(statsInfo)
+
+	status := &NodeMemoryStatus{
+		Addr:            addr,
+		UsedMemory:      infoInt(mem, "used_memory"),
+		MaxMemory:       infoInt(mem, "maxmemory"),
+		MaxMemoryPolicy: mem["maxmemory_policy"],
+		EvictedKeys:     infoInt(stats, "evicted_keys"),
+	}
+
+	if _, ok := mem["maxmemory"]; !ok {
+		result, err := c.ConfigGet(ctx, "maxmemory").Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get maxmemory of %s: %v", addr, err)
+		}
+		if n, err := strconv.ParseInt(result["maxmemory"], 10, 64); err == nil {
+			status.MaxMemory = n
+		}
+	}
+	if status.MaxMemoryPolicy == "" {
+		result, err := c.ConfigGet(ctx, "maxmemory-policy").Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get maxmemory-policy of %s: %v", addr, err)
+		}
+		status.MaxMemoryPolicy = result["maxmemory-policy"]
+	}
+
+	status.Utilization = utilization(status.UsedMemory, status.MaxMemory)
+	return status, nil
+}
+
+// utilization 计算内存使用率百分比，未设置上限时返回 0
+func utilization(used, max int64) float64 {
+	if max <= 0 {
+		return 0
+	}
+	return float64(used) / float64(max) * 100
+}
+
+// ValueSizeHistogram 扫描匹配 pattern 的 key，通过 MEMORY USAGE 统计每个 key 占用的字节数并按 buckets 分桶
+// buckets 为分桶上界（字节），返回的 map 以 "<=N" 为桶名，超过最大上界的计入 ">N"
+// 扫描过程中被删除的 key 会被跳过
+func ValueSizeHistogram(ctx context.Context, pattern string, buckets []int64) (map[string]int64, error) {
+	bounds := append([]int64(nil), buckets...)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	histogram := make(map[string]int64, len(bounds)+1)
+	for _, bound := range bounds {
+		histogram[fmt.Sprintf("<=%d", bound)] = 0
+	}
+	overflow := "+inf"
+	if len(bounds) > 0 {
+		overflow = fmt.Sprintf(">%d", bounds[len(bounds)-1])
+	}
+	histogram[overflow] = 0
+
+	var mu sync.Mutex
+	err := Scan(ctx, pattern, 100, func(keys []string) error {
+		usages, err := memoryUsages(ctx, keys)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, size := range usages {
+			i := sort.Search(len(bounds), func(i int) bool { return bounds[i] >= size })
+			if i < len(bounds) {
+				histogram[fmt.Sprintf("<=%d", bounds[i])]++
+			} else {
+				histogram[overflow]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return histogram, nil
+}
+
+// memoryUsages 以有限的并发度对 keys 执行 MEMORY USAGE，返回 key -> 字节数，不存在的 key 不会出现在结果中
+func memoryUsages(ctx context.Context, keys []string) (map[string]int64, error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	usages := make(map[string]int64, len(keys))
+	sem := make(chan struct{}, memorySampleConcurrency)
+
+	for _, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			size, err := Client.MemoryUsage(ctx, key).Result()
+			mu.Lock()
+			defer mu.Unlock()
+			if err == redis.Nil {
+				return
+			}
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to get memory usage of key %s: %v", key, err)
+				}
+				return
+			}
+			usages[key] = size
+		}(key)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return usages, nil
+}
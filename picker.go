@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// defaultZipfSkew 是 zipfian 分布的默认倾斜度，必须大于 1，越大访问越集中在靠前的 key
+const defaultZipfSkew = 1.1
+
+// KeyPicker 按给定分布随机挑选 key，用于压测时构造接近真实的热点访问模式，不访问 Redis，可以并发使用
+type KeyPicker struct {
+	mu   sync.Mutex
+	keys []string
+	rng  *rand.Rand
+	zipf *rand.Zipf // 为 nil 时使用均匀分布
+}
+
+// NewWeightedKeyPicker 创建 key 挑选器，distribution 支持 "uniform" 和 "zipfian"（未知的分布按 uniform 处理）
+// zipfian 分布下 keys 中越靠前的 key 被挑选的概率越高，倾斜度默认为 defaultZipfSkew，可通过 WithSkew 调整
+func NewWeightedKeyPicker(keys []string, distribution string) *KeyPicker {
+	p := &KeyPicker{
+		keys: keys,
+		rng:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	if distribution == "zipfian" && len(keys) > 0 {
+		p.zipf = rand.NewZipf(p.rng, defaultZipfSkew, 1, uint64(len(keys)-1))
+	}
+	return p
+}
+
+// WithSkew 设置 zipfian 分布的倾斜度，skew 必须大于 1，否则忽略；对 uniform 分布无效
+func (p *KeyPicker) WithSkew(skew float64) *KeyPicker {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.zipf != nil && skew > 1 {
+		p.zipf = rand.NewZipf(p.rng, skew, 1, uint64(len(p.keys)-1))
+	}
+	return p
+}
+
+// Pick 按分布随机返回一个 key，keys 为空时返回空字符串
+func (p *KeyPicker) Pick() string {
+	if len(p.keys) == 0 {
+		return ""
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.zipf != nil {
+		return p.keys[p.zipf.Uint64()]
+	}
+	return p.keys[p.rng.Intn(len(p.keys))]
+}
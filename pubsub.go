@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Subscriber 在一个 PubSub 连接上复用多个频道的订阅，按频道把消息分发给对应的 handler
+// 添加/移除 handler 时自动 SUBSCRIBE/UNSUBSCRIBE；连接断开后 go-redis 会自动重连并重新订阅所有频道
+// 所有 handler 都在同一个分发 goroutine 中串行调用，handler 执行过慢会阻塞所有频道的消息
+type Subscriber struct {
+	mu       sync.RWMutex
+	pubsub   *redis.PubSub
+	handlers map[string]func(msg *redis.Message)
+}
+
+// NewSubscriber 创建 Subscriber 并启动消息分发
+func NewSubscriber(ctx context.Context) *Subscriber {
+	s := &Subscriber{
+		pubsub:   Client.Subscribe(ctx),
+		handlers: make(map[string]func(msg *redis.Message)),
+	}
+	go s.dispatch()
+	return s
+}
+
+// Add 为 channel 注册 handler，channel 尚未订阅时执行 SUBSCRIBE；已注册的 handler 会被替换
+func (s *Subscriber) Add(ctx context.Context, channel string, handler func(msg *redis.Message)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.handlers[channel]; !ok {
+		if err := s.pubsub.Subscribe(ctx, channel); err != nil {
+			return fmt.Errorf("failed to subscribe to channel %s: %v", channel, err)
+		}
+	}
+	s.handlers[channel] = handler
+	return nil
+}
+
+// Remove 移除 channel 的 handler 并执行 UNSUBSCRIBE
+func (s *Subscriber) Remove(ctx context.Context, channel string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.handlers[channel]; !ok {
+		return nil
+	}
+	delete(s.handlers, channel)
+	if err := s.pubsub.Unsubscribe(ctx, channel); err != nil {
+		return fmt.Errorf("failed to unsubscribe from channel %s: %v", channel, err)
+	}
+	return nil
+}
+
+// Close 关闭底层的 PubSub 连接并停止分发
+func (s *Subscriber) Close() error {
+	return s.pubsub.Close()
+}
+
+// dispatch 将收到的消息分发给频道对应的 handler，PubSub 关闭后退出
+func (s *Subscriber) dispatch() {
+	for msg := range s.pubsub.Channel() {
+		s.mu.RLock()
+		handler := s.handlers[msg.Channel]
+		s.mu.RUnlock()
+		if handler != nil {
+			handler(msg)
+		}
+	}
+}
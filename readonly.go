@@ -0,0 +1,123 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// readOnlyClient 是只读客户端，通过 InitReadOnlyClient 初始化
+var readOnlyClient redis.UniversalClient
+
+// InitReadOnlyClient 初始化只读客户端，用于报表等只读查询，避免给主节点增加负载
+// Cluster 模式下开启 ReadOnly 与 RouteByLatency，读命令路由到延迟最低的节点（通常为副本）；
+// 单机模式下连接 ReplicaAddr，未配置时连接主客户端实际使用的地址。通过只读客户端发送写命令会返回 ErrReadOnlyClient
+func InitReadOnlyClient(ctx context.Context) error {
+	var c redis.UniversalClient
+	if config.IsCluster {
+		cluster := redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:                 config.Nodes,
+			Password:              config.Password,
+			TLSConfig:             config.TLSConfig,
+			ReadOnly:              true,
+			RouteByLatency:        true,
+			ContextTimeoutEnabled: true,
+			ReadTimeout:           -1, // 超时由 timeoutHook 控制，见 initSingleClient
+			WriteTimeout:          -1,
+			// 通过主客户端获取拓扑，并排除正在下线（DrainNode）的副本节点
+			ClusterSlots: readOnlyClusterSlots,
+		})
+		// 节点客户端不经过 ClusterClient 的 hook，需要在节点创建时单独安装
+		cluster.OnNewNode(func(node *redis.Client) {
+			node.AddHook(timeoutHook{})
+		})
+		c = cluster
+	} else {
+		addr := config.ReplicaAddr
+		if addr == "" {
+			addr = Client.(*redis.Client).Options().Addr
+		}
+		c = redis.NewClient(&redis.Options{
+			Addr:                  addr,
+			Password:              config.Password,
+			DB:                    config.DB,
+			TLSConfig:             config.TLSConfig,
+			ContextTimeoutEnabled: true,
+			ReadTimeout:           -1, // 超时由 timeoutHook 控制，见 initSingleClient
+			WriteTimeout:          -1,
+		})
+	}
+
+	// 通过 COMMAND 获取带 write 标志的命令，用于拒绝写命令
+	commands, err := c.Command(ctx).Result()
+	if err != nil {
+		c.Close()
+		return fmt.Errorf("failed to connect to Redis read-only client: %v", err)
+	}
+	writes := make(map[string]bool)
+	for name, info := range commands {
+		for _, flag := range info.Flags {
+			if flag == "write" {
+				writes[name] = true
+				break
+			}
+		}
+	}
+	c.AddHook(timeoutHook{})
+	c.AddHook(readOnlyHook{writes: writes})
+
+	readOnlyClient = c
+	fmt.Println("Connected to Redis read-only client")
+	return nil
+}
+
+// ReadOnlyClient 返回只读客户端，未调用 InitReadOnlyClient 时返回 nil
+func ReadOnlyClient() redis.UniversalClient {
+	return readOnlyClient
+}
+
+// readOnlyHook 拒绝带 write 标志的命令
+type readOnlyHook struct {
+	writes map[string]bool
+}
+
+func (h readOnlyHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h readOnlyHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.check(cmd); err != nil {
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h readOnlyHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			if err := h.check(cmd); err != nil {
+				for _, c := range cmds {
+					c.SetErr(err)
+				}
+				return err
+			}
+		}
+		return next(ctx, cmds)
+	}
+}
+
+// check 命令为写命令时设置并返回 ErrReadOnlyClient
+func (h readOnlyHook) check(cmd redis.Cmder) error {
+	if !h.writes[cmd.Name()] {
+		return nil
+	}
+	err := fmt.Errorf("%w: %s", ErrReadOnlyClient, cmd.Name())
+	cmd.SetErr(err)
+	return err
+}
@@ -2,19 +2,30 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
 	"sync"
+	"time"
 )
 
 // RedisConfig 用于存储 Redis 配置
 type RedisConfig struct {
-	IsCluster bool     `mapstructure:"is_cluster"`
-	Nodes     []string `mapstructure:"nodes"` // 用于 Cluster 模式
-	Addr      string   `mapstructure:"addr"`
-	Password  string   `mapstructure:"password"`
-	DB        int      `mapstructure:"db"`
+	IsCluster bool        `mapstructure:"is_cluster"`
+	Nodes     []string    `mapstructure:"nodes"` // 用于 Cluster 模式
+	Addr      string      `mapstructure:"addr"`
+	Password  string      `mapstructure:"password"`
+	DB        int         `mapstructure:"db"`
+	TLSConfig *tls.Config `mapstructure:"-"` // 非空时使用 TLS 连接，只能通过代码设置
+
+	ReplicaAddr   string   `mapstructure:"replica_addr"`   // 单机模式下只读客户端连接的副本地址，为空时使用 Addr
+	FallbackAddrs []string `mapstructure:"fallback_addrs"` // 单机模式下 Addr 初始连接失败时依次尝试的备用地址
+
+	EnableLocalFallback bool `mapstructure:"enable_local_fallback"` // 开启本地降级缓存，见 localFallback
+	LocalFallbackSize   int  `mapstructure:"local_fallback_size"`   // 本地降级缓存容量，默认 10000
+
+	AllowDestructiveWildcard bool `mapstructure:"allow_destructive_wildcard"` // 允许修改类批量操作使用只由通配符组成的模式（如 "*"）
 }
 
 // Client 是全局的 Redis 客户端
@@ -41,8 +52,18 @@ func InitRedisConfig(filePath string, fileName string, format string) error {
 	return nil
 }
 
+// InitRedisClientWithConfig 使用给定的配置初始化 Redis 客户端，不需要 viper 或配置文件
+func InitRedisClientWithConfig(ctx context.Context, cfg RedisConfig) error {
+	config = cfg
+	return InitRedisClient(ctx)
+}
+
 // InitRedisClient 初始化 Redis 客户端
 func InitRedisClient(ctx context.Context) error {
+	localFallback = nil
+	if config.EnableLocalFallback {
+		localFallback = newLocalCache(config.LocalFallbackSize)
+	}
 	if config.IsCluster {
 		return initClusterClient(ctx, &config)
 	}
@@ -50,28 +71,64 @@ func InitRedisClient(ctx context.Context) error {
 }
 
 // initSingleClient 初始化单机模式 Redis 客户端
+// Addr 连接失败时依次尝试 FallbackAddrs，使用第一个能 PING 通的地址
 func initSingleClient(ctx context.Context, config *RedisConfig) error {
-	Client = redis.NewClient(&redis.Options{
-		Addr:     config.Addr,
-		Password: config.Password,
-		DB:       config.DB,
-	})
+	addrs := append([]string{config.Addr}, config.FallbackAddrs...)
+	var err error
+	for i, addr := range addrs {
+		c := redis.NewClient(&redis.Options{
+			Addr:      addr,
+			Password:  config.Password,
+			DB:        config.DB,
+			TLSConfig: config.TLSConfig,
+			// 让 ctx 的 deadline 作用于网络读写，配合 timeoutHook 实现命令超时；
+			// 关闭 go-redis 自身的读写超时，否则 WithTimeout 无法把超时延长到默认的 3s 之后
+			ContextTimeoutEnabled: true,
+			ReadTimeout:           -1,
+			WriteTimeout:          -1,
+		})
+		c.AddHook(timeoutHook{})
+		c.AddHook(hotKeyHook{})
 
-	if err := Client.Ping(ctx).Err(); err != nil {
-		return fmt.Errorf("failed to connect to Redis: %v", err)
-	}
+		if err = c.Ping(ctx).Err(); err != nil {
+			c.Close()
+			if len(addrs) > 1 {
+				fmt.Printf("Failed to connect to Redis at %s: %v\n", addr, err)
+			}
+			continue
+		}
 
-	fmt.Println("Connected to Redis in single node mode")
-	return nil
+		Client = c
+		if i > 0 {
+			fmt.Printf("Connected to Redis in single node mode using fallback address %s\n", addr)
+		} else {
+			fmt.Println("Connected to Redis in single node mode")
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to connect to Redis: %v", err)
 }
 
 // initClusterClient 初始化 Cluster 模式 Redis 客户端
 func initClusterClient(ctx context.Context, config *RedisConfig) error {
 	Client = redis.NewClusterClient(&redis.ClusterOptions{
-		Addrs:    config.Nodes,
-		Password: config.Password,
+		Addrs:     config.Nodes,
+		Password:  config.Password,
+		TLSConfig: config.TLSConfig,
+		// 让 ctx 的 deadline 作用于网络读写，配合 timeoutHook 实现命令超时；
+		// 关闭 go-redis 自身的读写超时，否则 WithTimeout 无法把超时延长到默认的 3s 之后
+		ContextTimeoutEnabled: true,
+		ReadTimeout:           -1,
+		WriteTimeout:          -1,
 	})
 	ClusterClient = Client.(*redis.ClusterClient)
+	ClusterClient.AddHook(timeoutHook{})
+	// ForEachMaster/MasterForKey 直接使用节点客户端，不经过 ClusterClient 的 hook；
+	// hotKeyHook 只安装在节点上，经过 ClusterClient 的命令最终也由节点执行，避免被记录两次
+	ClusterClient.OnNewNode(func(node *redis.Client) {
+		node.AddHook(timeoutHook{})
+		node.AddHook(hotKeyHook{})
+	})
 
 	if err := Client.Ping(ctx).Err(); err != nil {
 		return fmt.Errorf("failed to connect to Redis Cluster: %v", err)
@@ -87,82 +144,156 @@ func GetClient() redis.UniversalClient {
 }
 
 // 根据模式选择 Redis 客户端 执行 Scan 命令
+// Cluster 模式下通过 ForEachMaster 在所有主节点上并发扫描，fn 可能被并发调用；所有节点扫描结束后返回汇总的错误
 func Scan(ctx context.Context, pattern string, count int64, fn func(keys []string) error) error {
-	if config.IsCluster {
-		var wg sync.WaitGroup
-		var mu sync.Mutex
-		var firstErr error
-
-		err := ClusterClient.ForEachMaster(ctx, func(context context.Context, master *redis.Client) error {
-			wg.Add(1)
-			go func(master *redis.Client) {
-				defer wg.Done()
-				var cursor uint64 = 0
-				for {
-					k, c, err := master.Scan(ctx, cursor, pattern, count).Result()
-					if err != nil {
-						mu.Lock()
-						if firstErr == nil {
-							firstErr = err
-						}
-						fmt.Println("Error scanning keys: ", err)
-						mu.Unlock()
-						return
-					}
-
-					if err := fn(k); err != nil {
-						mu.Lock()
-						if firstErr == nil {
-							firstErr = err
-						}
-						mu.Unlock()
-						return
-					}
-					// 如果 cursor 为 0，表示扫描完成
-					if c == 0 {
-						fmt.Printf("Scan completed on master: %v\n", master)
-						break
-					}
-					cursor = c
-				}
-			}(master)
-			return nil
-		})
-		wg.Wait()
-		if firstErr != nil {
-			return firstErr
-		}
+	if err := checkPattern(pattern); err != nil {
 		return err
-	} else {
+	}
+	return ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
 		var cursor uint64 = 0
 		for {
-			keys, c, err := Client.Scan(ctx, cursor, pattern, count).Result()
+			keys, c, err := node.Scan(ctx, cursor, pattern, count).Result()
 			if err != nil {
 				fmt.Println("Error scanning keys: ", err)
 				return err
 			}
-			err = fn(keys)
-			if err != nil {
+			if err := fn(keys); err != nil {
 				return err
 			}
+			// 如果 cursor 为 0，表示扫描完成
 			if c == 0 {
-				fmt.Println("Scan completed")
-				break
+				fmt.Printf("Scan completed on %s\n", node.Options().Addr)
+				return nil
 			}
 			cursor = c
 		}
+	})
+}
+
+const (
+	// adaptiveMinCount 与 adaptiveMaxCount 是 ScanAdaptive 中 COUNT 的取值范围
+	adaptiveMinCount = 10
+	adaptiveMaxCount = 10000
+)
+
+// ScanAdaptive 与 Scan 相同，但会自动调整 SCAN 的 COUNT：从较小的 COUNT 开始，
+// 单次迭代（SCAN + fn）耗时低于 targetLatency 的一半时翻倍，超过 targetLatency 时减半，
+// 使每次迭代的耗时接近 targetLatency。Cluster 模式下每个主节点独立调整
+func ScanAdaptive(ctx context.Context, pattern string, targetLatency time.Duration, fn func(keys []string) error) error {
+	if err := checkPattern(pattern); err != nil {
+		return err
+	}
+	scanNode := func(ctx context.Context, c redis.Cmdable) error {
+		var cursor uint64 = 0
+		var count int64 = adaptiveMinCount
+		for {
+			start := time.Now()
+			keys, next, err := c.Scan(ctx, cursor, pattern, count).Result()
+			if err != nil {
+				return err
+			}
+			if err := fn(keys); err != nil {
+				return err
+			}
+			if next == 0 {
+				return nil
+			}
+			cursor = next
+
+			elapsed := time.Since(start)
+			if elapsed < targetLatency/2 {
+				count = min(count*2, adaptiveMaxCount)
+			} else if elapsed > targetLatency {
+				count = max(count/2, adaptiveMinCount)
+			}
+		}
+	}
+
+	return ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		return scanNode(ctx, node)
+	})
+}
+
+// ScanDB 在指定的 db 上执行 Scan，不会切换主客户端使用的 DB
+// 内部使用一个与主客户端配置相同、仅 DB 不同的临时客户端，扫描结束后关闭；Cluster 模式只有 db 0，返回 ErrClusterUnsupported
+func ScanDB(ctx context.Context, db int, pattern string, count int64, fn func(keys []string) error) error {
+	if config.IsCluster {
+		return ErrClusterUnsupported
+	}
+	if err := checkPattern(pattern); err != nil {
+		return err
+	}
+	opts := *Client.(*redis.Client).Options()
+	opts.DB = db
+	c := redis.NewClient(&opts)
+	defer c.Close()
+	c.AddHook(timeoutHook{})
+
+	var cursor uint64 = 0
+	for {
+		keys, next, err := c.Scan(ctx, cursor, pattern, count).Result()
+		if err != nil {
+			return fmt.Errorf("failed to scan db %d: %v", db, err)
+		}
+		if err := fn(keys); err != nil {
+			return err
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// ScanUnique 与 Scan 相同，但会对所有主节点返回的 key 去重后再分批（每批 count 个）调用 fn
+// Cluster 模式下如果扫描期间发生 reshard，key 在主节点间迁移可能导致 Scan 重复返回同一个 key，
+// ScanUnique 保证每个 key 只交给 fn 一次；但 SCAN 本身的语义仍然成立：扫描期间新增或迁移的 key 可能被遗漏
+// 注意：所有匹配的 key 会先收集到内存中，内存开销与匹配的 key 数量成正比，不适合超大规模的 key 空间
+func ScanUnique(ctx context.Context, pattern string, count int64, fn func(keys []string) error) error {
+	var mu sync.Mutex
+	seen := make(map[string]struct{})
+	err := Scan(ctx, pattern, count, func(keys []string) error {
+		mu.Lock()
+		for _, key := range keys {
+			seen[key] = struct{}{}
+		}
+		mu.Unlock()
 		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if count <= 0 {
+		count = 10
 	}
+	batch := make([]string, 0, count)
+	for key := range seen {
+		batch = append(batch, key)
+		if int64(len(batch)) >= count {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]string, 0, count)
+		}
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
 }
 
 func Type(ctx context.Context, key string) (string, error) {
+	if err := checkKeys(key); err != nil {
+		return "", err
+	}
 	if config.IsCluster {
 		result, err := ClusterClient.Type(ctx, key).Result()
 		if err != nil {
 			return "", fmt.Errorf("failed to get type of key %s: %v", key, err)
 		}
 		if result == "none" {
-			return "", fmt.Errorf("key %s does not exist", key)
+			return "", fmt.Errorf("%w: %s", ErrKeyNotFound, key)
 		}
 		return result, nil
 
@@ -172,24 +303,93 @@ func Type(ctx context.Context, key string) (string, error) {
 			return "", fmt.Errorf("failed to get type of key %s: %v", key, err)
 		}
 		if typ == "none" {
-			return "", fmt.Errorf("key %s does not exist", key)
+			return "", fmt.Errorf("%w: %s", ErrKeyNotFound, key)
 		}
 		return typ, nil
 	}
 }
 
 func Get(ctx context.Context, key string) (string, error) {
+	if err := checkKeys(key); err != nil {
+		return "", err
+	}
+	if b := autoBatcherFrom(ctx); b != nil {
+		return getBatched(ctx, b, key)
+	}
 	if config.IsCluster {
 		result, err := ClusterClient.Get(ctx, key).Result()
 		if err != nil {
+			if value, ok := localFallbackGet(ctx, key, err); ok {
+				return value, nil
+			}
 			return "", fmt.Errorf("failed to get value of key %s: %v", key, err)
 		}
 		return result, nil
 	} else {
 		result, err := Client.Get(ctx, key).Result()
 		if err != nil {
+			if value, ok := localFallbackGet(ctx, key, err); ok {
+				return value, nil
+			}
 			return "", fmt.Errorf("failed to get value of key %s: %v", key, err)
 		}
 		return result, nil
 	}
 }
+
+// Set 写入 key，ttl 为 0 表示不过期；开启本地降级缓存时同步写入本地缓存
+func Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	if config.IsCluster {
+		if err := ClusterClient.Set(ctx, key, value, ttl).Err(); err != nil {
+			return fmt.Errorf("failed to set value of key %s: %v", key, err)
+		}
+	} else {
+		if err := Client.Set(ctx, key, value, ttl).Err(); err != nil {
+			return fmt.Errorf("failed to set value of key %s: %v", key, err)
+		}
+	}
+	localFallbackSet(key, value, ttl)
+	return nil
+}
+
+// GetAny 根据 key 的类型读取其全部内容，返回值的具体类型为：
+// string -> string，list -> []string，set -> []string，hash -> map[string]string，
+// zset -> []redis.Z（按 score 升序），stream -> []redis.XMessage
+// key 不存在时返回 ErrKeyNotFound
+func GetAny(ctx context.Context, key string) (interface{}, error) {
+	if err := checkKeys(key); err != nil {
+		return nil, err
+	}
+	typ, err := Type(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	switch typ {
+	case "string":
+		result, err = Client.Get(ctx, key).Result()
+	case "list":
+		result, err = Client.LRange(ctx, key, 0, -1).Result()
+	case "set":
+		result, err = Client.SMembers(ctx, key).Result()
+	case "hash":
+		result, err = Client.HGetAll(ctx, key).Result()
+	case "zset":
+		result, err = Client.ZRangeWithScores(ctx, key, 0, -1).Result()
+	case "stream":
+		result, err = Client.XRange(ctx, key, "-", "+").Result()
+	default:
+		return nil, fmt.Errorf("unsupported type %s of key %s", typ, key)
+	}
+	if err == redis.Nil {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get value of key %s: %v", key, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SetQuorum 写入 key 后通过 WAIT 等待该分片多数副本确认，在 timeout 内确认数不足时返回 ErrQuorumNotReached
+// Cluster 模式下副本数量来自 CLUSTER SHARDS 中 key 所在分片的副本节点；单机模式下来自 INFO replication 的 connected_slaves
+// 没有副本时等同于普通 SET。注意：返回错误时写入已经在主节点生效，只是持久性没有达到要求
+func SetQuorum(ctx context.Context, key string, value interface{}, ttl time.Duration, timeout time.Duration) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	if timeout <= 0 {
+		// WAIT n 0 会一直阻塞
+		return fmt.Errorf("invalid quorum timeout %v: must be positive", timeout)
+	}
+	var node *redis.Client
+	var replicas int
+	if config.IsCluster {
+		master, err := ClusterClient.MasterForKey(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to get master of key %s: %v", key, err)
+		}
+		node = master
+		replicas, err = shardReplicas(ctx, keySlot(key))
+		if err != nil {
+			return err
+		}
+	} else {
+		node = Client.(*redis.Client)
+		info, err := Client.Info(ctx, "replication").Result()
+		if err != nil {
+			return fmt.Errorf("failed to get replication info: %v", err)
+		}
+		replicas = int(infoInt(parseInfo(info), "connected_slaves"))
+	}
+
+	quorum := 0
+	if replicas > 0 {
+		quorum = replicas/2 + 1
+	}
+
+	// SET 与 WAIT 必须在同一个连接上执行，WAIT 只等待当前连接之前的写入；
+	// WAIT 不放入 pipeline，单独发送时 go-redis 会按 timeout 设置读超时
+	conn := node.Conn()
+	defer conn.Close()
+	if err := conn.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set key %s with quorum: %v", key, err)
+	}
+	invalidateLocal(key)
+	if quorum == 0 {
+		return nil
+	}
+	acked, err := conn.Wait(ctx, quorum, timeout).Result()
+	if err != nil {
+		return fmt.Errorf("failed to wait for replicas of key %s: %v", key, err)
+	}
+	if acked < int64(quorum) {
+		return fmt.Errorf("%w: key %s acknowledged by %d of %d replicas, need %d", ErrQuorumNotReached, key, acked, replicas, quorum)
+	}
+	return nil
+}
+
+// shardReplicas 通过 CLUSTER SHARDS 返回负责 slot 的分片中副本节点的数量
+func shardReplicas(ctx context.Context, slot int) (int, error) {
+	shards, err := ClusterClient.ClusterShards(ctx).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cluster shards: %v", err)
+	}
+	for _, shard := range shards {
+		for _, r := range shard.Slots {
+			if int64(slot) < r.Start || int64(slot) > r.End {
+				continue
+			}
+			replicas := 0
+			for _, node := range shard.Nodes {
+				if node.Role == "replica" {
+					replicas++
+				}
+			}
+			return replicas, nil
+		}
+	}
+	return 0, fmt.Errorf("no shard serves slot %d", slot)
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// overlapBatchSize 是 OverlapMatrix 每个 pipeline 中 SINTERCARD 命令的最大数量
+const overlapBatchSize = 100
+
+// SRandMember 随机返回集合中的 count 个成员，不会像 SPOP 那样移除成员
+// count 为正数时返回不重复的成员（最多为集合大小）；
+// count 为负数时返回 |count| 个成员，允许重复
+func SRandMember(ctx context.Context, key string, count int) ([]string, error) {
+	if err := checkKeys(key); err != nil {
+		return nil, err
+	}
+	if config.IsCluster {
+		result, err := ClusterClient.SRandMemberN(ctx, key, int64(count)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get random members of set %s: %v", key, err)
+		}
+		return result, nil
+	} else {
+		result, err := Client.SRandMemberN(ctx, key, int64(count)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get random members of set %s: %v", key, err)
+		}
+		return result, nil
+	}
+}
+
+// OverlapMatrix 计算 setKeys 两两之间的交集大小（SINTERCARD），返回对称矩阵，对角线为集合自身的大小
+// limit 大于 0 时传给 SINTERCARD 的 LIMIT，交集大小达到 limit 即停止计算，用于限制超大集合的开销
+// 命令按每批 overlapBatchSize 个分批 pipeline 执行；Cluster 模式下所有 key 必须位于同一个哈希槽
+func OverlapMatrix(ctx context.Context, setKeys []string, limit int64) (map[string]map[string]int64, error) {
+	if err := checkKeys(setKeys...); err != nil {
+		return nil, err
+	}
+	if err := checkSameSlot(setKeys...); err != nil {
+		return nil, err
+	}
+
+	type pair struct{ a, b string }
+	var pairs []pair
+	for i := range setKeys {
+		for j := i; j < len(setKeys); j++ {
+			pairs = append(pairs, pair{setKeys[i], setKeys[j]})
+		}
+	}
+
+	matrix := make(map[string]map[string]int64, len(setKeys))
+	for _, key := range setKeys {
+		matrix[key] = make(map[string]int64, len(setKeys))
+	}
+	for start := 0; start < len(pairs); start += overlapBatchSize {
+		batch := pairs[start:min(start+overlapBatchSize, len(pairs))]
+		cmds := make([]*redis.IntCmd, len(batch))
+		_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, p := range batch {
+				keys := []string{p.a, p.b}
+				if p.a == p.b {
+					keys = keys[:1]
+				}
+				cmds[i] = withFirstKey(redis.NewIntCmd(ctx, numKeysArgs("sintercard", keys, "limit", limit)...))
+				_ = pipe.Process(ctx, cmds[i])
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute set intersections: %v", err)
+		}
+		for i, p := range batch {
+			matrix[p.a][p.b] = cmds[i].Val()
+			matrix[p.b][p.a] = cmds[i].Val()
+		}
+	}
+	return matrix, nil
+}
@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// clusterSlots 是 Redis Cluster 的哈希槽数量
+const clusterSlots = 16384
+
+// keySlot 计算 key 所属的哈希槽（CRC16 mod 16384），支持 {hashtag}
+func keySlot(key string) int {
+	return int(crc16(hashTag(key)) % clusterSlots)
+}
+
+// hashTag 返回 key 中参与哈希槽计算的部分：存在非空的 {...} 时取第一个 {...} 中的内容，否则为整个 key
+func hashTag(key string) string {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return key
+	}
+	end := strings.IndexByte(key[start+1:], '}')
+	if end <= 0 {
+		return key
+	}
+	return key[start+1 : start+1+end]
+}
+
+// crc16 实现 Redis Cluster 使用的 CRC16-CCITT (XMODEM)
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// groupKeysBySlot 按哈希槽对 key 分组，便于按槽批量执行 pipeline
+// 单机模式下所有 key 放在同一组
+func groupKeysBySlot(keys []string) map[int][]string {
+	groups := make(map[int][]string)
+	if !config.IsCluster {
+		if len(keys) > 0 {
+			groups[0] = keys
+		}
+		return groups
+	}
+	for _, key := range keys {
+		slot := keySlot(key)
+		groups[slot] = append(groups[slot], key)
+	}
+	return groups
+}
+
+// SameSlot 返回所有 key 是否位于同一个哈希槽以及该槽（CRC16 mod 16384，支持 {hashtag}）
+// 纯本地计算，不访问网络，可在执行多 key 命令或 Lua 脚本前校验；不在同一槽时返回第一个 key 的槽，没有 key 时返回 true 和 -1
+func SameSlot(keys ...string) (bool, int) {
+	if len(keys) == 0 {
+		return true, -1
+	}
+	slot := keySlot(keys[0])
+	for _, key := range keys[1:] {
+		if keySlot(key) != slot {
+			return false, slot
+		}
+	}
+	return true, slot
+}
+
+// checkSameSlot 在 Cluster 模式下校验所有 key 位于同一个哈希槽，否则返回 ErrCrossSlot
+func checkSameSlot(keys ...string) error {
+	if !config.IsCluster {
+		return nil
+	}
+	if ok, slot := SameSlot(keys...); !ok {
+		return fmt.Errorf("%w: keys %v do not all hash to slot %d", ErrCrossSlot, keys, slot)
+	}
+	return nil
+}
+
+// numKeysArgs 构造以 numkeys 开头的命令（LMPOP/ZMPOP/ZINTERCARD/SINTERCARD）的参数：name numkeys key... rest...
+// 这类命令需要通过 withFirstKey 标记第一个 key 的位置，否则 ClusterClient 会按 numkeys 路由并依赖 MOVED 重定向，多一次往返
+func numKeysArgs(name string, keys []string, rest ...interface{}) []interface{} {
+	args := make([]interface{}, 0, 2+len(keys)+len(rest))
+	args = append(args, name, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	return append(args, rest...)
+}
+
+// withFirstKey 将 numKeysArgs 构造的命令的第一个 key 位置设置为 2，命令仍经由 ClusterClient 发送，保留 MOVED/ASK 处理
+func withFirstKey[T redis.Cmder](cmd T) T {
+	cmd.SetFirstKeyPos(2)
+	return cmd
+}
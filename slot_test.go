@@ -0,0 +1,69 @@
+package redis
+
+import "testing"
+
+func TestCRC16(t *testing.T) {
+	if got := crc16("123456789"); got != 0x31C3 {
+		t.Fatalf("crc16(\"123456789\") = %#x; want 0x31c3", got)
+	}
+}
+
+func TestHashTag(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"foo", "foo"},
+		{"{user1000}.following", "user1000"},
+		{"foo{bar}zap", "bar"},
+		{"{}", "{}"},           // 空 hashtag，整个 key 参与哈希
+		{"a{}b", "a{}b"},       // 空 hashtag
+		{"{a}{b}", "a"},        // 只取第一对花括号
+		{"foo{bar", "foo{bar"}, // 没有闭合的花括号
+		{"foo{{bar}}zap", "{bar"},
+	}
+	for _, tt := range tests {
+		if got := hashTag(tt.key); got != tt.want {
+			t.Errorf("hashTag(%q) = %q; want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestKeySlot(t *testing.T) {
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"foo", 12182},
+		{"bar", 5061},
+		{"a", 15495},
+		{"{a}{b}", 15495},
+		{"x{a}y", 15495},
+	}
+	for _, tt := range tests {
+		if got := keySlot(tt.key); got != tt.want {
+			t.Errorf("keySlot(%q) = %d; want %d", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestSameSlot(t *testing.T) {
+	tests := []struct {
+		keys     []string
+		wantSame bool
+		wantSlot int
+	}{
+		{nil, true, -1},
+		{[]string{"foo"}, true, 12182},
+		{[]string{"{user1000}.following", "{user1000}.followers"}, true, keySlot("user1000")},
+		{[]string{"foo", "bar"}, false, 12182},
+		{[]string{"a", "{a}{b}", "{a}x"}, true, 15495},
+		{[]string{"a{}b", "{a}"}, false, keySlot("a{}b")},
+	}
+	for _, tt := range tests {
+		same, slot := SameSlot(tt.keys...)
+		if same != tt.wantSame || slot != tt.wantSlot {
+			t.Errorf("SameSlot(%q) = %v, %d; want %v, %d", tt.keys, same, slot, tt.wantSame, tt.wantSlot)
+		}
+	}
+}
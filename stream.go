@@ -0,0 +1,253 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// streamMaxFailures 是 StreamConsumer 最多记录的失败原因数量。被其他消费者认领并 ACK 的消息不会经过本消费者的 ack，
+// 记录不会被删除，超出上限时丢弃最早的消息（ID 最小）的记录
+const streamMaxFailures = 10000
+
+// StreamConsumerOptions 消费者组消费者的配置，零值字段使用默认值
+type StreamConsumerOptions struct {
+	Count            int64         // 每次读取的消息数，默认 10
+	Block            time.Duration // XREADGROUP 的阻塞时间，默认 5s
+	MinIdle          time.Duration // 处理失败的消息需要空闲多久才会被重新投递，默认 30s
+	MaxDeliveries    int64         // 投递次数达到该值的消息会被移入死信流，0 表示不启用死信
+	DeadLetterStream string        // 死信流名称，默认为 "<stream>:dlq"
+}
+
+// StreamConsumer 基于消费者组的 Stream 消费者
+// handler 返回错误的消息不会被 ACK，空闲超过 MinIdle 后会通过 XCLAIM 重新投递；
+// 投递次数（XPENDING 的 delivery count）达到 MaxDeliveries 时，消息会连同原始 ID 和失败原因写入死信流并从主消费者组 ACK，
+// 避免毒消息永远阻塞消费者组
+type StreamConsumer struct {
+	stream   string
+	group    string
+	consumer string
+	opts     StreamConsumerOptions
+
+	mu       sync.Mutex
+	failures map[string]string // 消息 ID -> 本消费者记录的最近一次失败原因
+}
+
+// NewStreamConsumer 创建消费者，消费者组不存在时会在 Run 中自动创建；stream 为空时 Run 返回 ErrEmptyKey
+func NewStreamConsumer(stream, group, consumer string, opts StreamConsumerOptions) *StreamConsumer {
+	if opts.Count <= 0 {
+		opts.Count = 10
+	}
+	if opts.Block <= 0 {
+		opts.Block = 5 * time.Second
+	}
+	if opts.MinIdle <= 0 {
+		opts.MinIdle = 30 * time.Second
+	}
+	if opts.DeadLetterStream == "" {
+		opts.DeadLetterStream = stream + ":dlq"
+	}
+	return &StreamConsumer{
+		stream:   stream,
+		group:    group,
+		consumer: consumer,
+		opts:     opts,
+		failures: make(map[string]string),
+	}
+}
+
+// Run 持续消费消息直到 ctx 结束
+func (c *StreamConsumer) Run(ctx context.Context, handler func(ctx context.Context, msg redis.XMessage) error) error {
+	if err := checkKeys(c.stream); err != nil {
+		return err
+	}
+	err := Client.XGroupCreateMkStream(ctx, c.stream, c.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create group %s of stream %s: %v", c.group, c.stream, err)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := c.retryPending(ctx, handler); err != nil {
+			return err
+		}
+
+		streams, err := Client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.group,
+			Consumer: c.consumer,
+			Streams:  []string{c.stream, ">"},
+			Count:    c.opts.Count,
+			Block:    c.opts.Block,
+		}).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to read group %s of stream %s: %v", c.group, c.stream, err)
+		}
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				if err := c.handle(ctx, msg, handler); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// retryPending 重新投递空闲超过 MinIdle 的待处理消息，投递次数达到上限的消息移入死信流
+func (c *StreamConsumer) retryPending(ctx context.Context, handler func(ctx context.Context, msg redis.XMessage) error) error {
+	pending, err := Client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: c.stream,
+		Group:  c.group,
+		Idle:   c.opts.MinIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  c.opts.Count,
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get pending messages of stream %s: %v", c.stream, err)
+	}
+
+	var retry []string
+	for _, p := range pending {
+		if c.opts.MaxDeliveries > 0 && p.RetryCount >= c.opts.MaxDeliveries {
+			if err := c.deadLetter(ctx, p.ID, p.RetryCount); err != nil {
+				return err
+			}
+			continue
+		}
+		retry = append(retry, p.ID)
+	}
+	if len(retry) == 0 {
+		return nil
+	}
+
+	msgs, err := Client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   c.stream,
+		Group:    c.group,
+		Consumer: c.consumer,
+		MinIdle:  c.opts.MinIdle,
+		Messages: retry,
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to claim pending messages of stream %s: %v", c.stream, err)
+	}
+	for _, msg := range msgs {
+		if err := c.handle(ctx, msg, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handle 调用 handler，成功时 ACK，失败时记录失败原因等待重新投递
+func (c *StreamConsumer) handle(ctx context.Context, msg redis.XMessage, handler func(ctx context.Context, msg redis.XMessage) error) error {
+	if err := handler(ctx, msg); err != nil {
+		c.mu.Lock()
+		if _, ok := c.failures[msg.ID]; !ok && len(c.failures) >= streamMaxFailures {
+			delete(c.failures, oldestStreamID(c.failures))
+		}
+		c.failures[msg.ID] = err.Error()
+		c.mu.Unlock()
+		return nil
+	}
+	return c.ack(ctx, msg.ID)
+}
+
+// deadLetter 将消息连同原始 ID、失败原因和投递次数写入死信流，然后从主消费者组 ACK
+// 写入死信流与 ACK 不是原子的：两步之间失败时消息可能在死信流中出现两次，但不会丢失
+func (c *StreamConsumer) deadLetter(ctx context.Context, id string, deliveries int64) error {
+	msgs, err := Client.XRangeN(ctx, c.stream, id, id, 1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read message %s of stream %s: %v", id, c.stream, err)
+	}
+	if len(msgs) > 0 {
+		c.mu.Lock()
+		reason, ok := c.failures[id]
+		c.mu.Unlock()
+		if !ok {
+			reason = "unknown"
+		}
+
+		values := make(map[string]interface{}, len(msgs[0].Values)+3)
+		for k, v := range msgs[0].Values {
+			values[k] = v
+		}
+		values["original_id"] = id
+		values["failure_reason"] = reason
+		values["delivery_count"] = deliveries
+
+		err := Client.XAdd(ctx, &redis.XAddArgs{Stream: c.opts.DeadLetterStream, Values: values}).Err()
+		if err != nil {
+			return fmt.Errorf("failed to add message %s to dead letter stream %s: %v", id, c.opts.DeadLetterStream, err)
+		}
+	}
+	return c.ack(ctx, id)
+}
+
+// oldestStreamID 返回 failures 中最小的消息 ID
+func oldestStreamID(failures map[string]string) string {
+	var oldest string
+	var oldestMs, oldestSeq uint64
+	for id := range failures {
+		ms, seq := parseStreamID(id)
+		if oldest == "" || ms < oldestMs || (ms == oldestMs && seq < oldestSeq) {
+			oldest, oldestMs, oldestSeq = id, ms, seq
+		}
+	}
+	return oldest
+}
+
+// parseStreamID 解析 "<ms>-<seq>" 形式的消息 ID，格式不合法的部分按 0 处理
+func parseStreamID(id string) (ms, seq uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ = strconv.ParseUint(msPart, 10, 64)
+	seq, _ = strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
+
+// ack 从消费者组 ACK 消息并清理失败记录
+func (c *StreamConsumer) ack(ctx context.Context, id string) error {
+	if err := Client.XAck(ctx, c.stream, c.group, id).Err(); err != nil {
+		return fmt.Errorf("failed to ack message %s of stream %s: %v", id, c.stream, err)
+	}
+	c.mu.Lock()
+	delete(c.failures, id)
+	c.mu.Unlock()
+	return nil
+}
+
+// streamIDPattern 匹配 "<ms>-<seq>" 或 "<ms>" 形式的 Stream ID
+var streamIDPattern = regexp.MustCompile(`^[0-9]+(-[0-9]+)?$`)
+
+// XSetID 设置 Stream 的最后 ID（last-delivered ID），用于从备份恢复 Stream 后推进 ID 生成器，避免新条目与恢复的条目冲突
+// id 必须是明确的 "<ms>-<seq>" 或 "<ms>"；XSETID 不接受 "*" 这类自动生成的 ID。
+// id 小于 Stream 中已有的最大条目 ID 时返回 ErrStreamIDTooSmall
+func XSetID(ctx context.Context, stream, id string) error {
+	if err := checkKeys(stream); err != nil {
+		return err
+	}
+	if !streamIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid stream id %q: must be <ms>-<seq> or <ms>", id)
+	}
+	err := Client.Do(ctx, "xsetid", stream, id).Err()
+	if err != nil && strings.Contains(err.Error(), "smaller than") {
+		return fmt.Errorf("%w: stream %s, id %s", ErrStreamIDTooSmall, stream, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set id of stream %s: %v", stream, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ExpectMissing 作为 CompareAndSwap 的 expected 参数时，表示仅当 key 不存在时才写入
+const ExpectMissing = "\x00redis-client:expect-missing\x00"
+
+// casScript 当 key 的当前值等于 ARGV[2]（ARGV[1] 为 "1" 时要求 key 不存在）时写入 ARGV[3]，
+// ARGV[4] 为毫秒级 TTL，0 表示不过期
+var casScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if ARGV[1] == '1' then
+	if current then
+		return 0
+	end
+elseif current ~= ARGV[2] then
+	return 0
+end
+local ttl = tonumber(ARGV[4])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[3], 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], ARGV[3])
+end
+return 1
+`)
+
+// CompareAndSwap 仅当 key 的当前值等于 expected 时将其设置为 new，返回是否替换成功
+// expected 传 ExpectMissing 时表示仅当 key 不存在时写入；ttl 为 0 表示不过期
+func CompareAndSwap(ctx context.Context, key, expected, new string, ttl time.Duration) (bool, error) {
+	if err := checkKeys(key); err != nil {
+		return false, err
+	}
+	missing := "0"
+	if expected == ExpectMissing {
+		missing = "1"
+	}
+	swapped, err := casScript.Run(ctx, Client, []string{key}, missing, expected, new, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to compare and swap key %s: %v", key, err)
+	}
+	if swapped == 1 {
+		invalidateLocal(key)
+	}
+	return swapped == 1, nil
+}
+
+// readAndTickScript 自增计数器，首次自增（或计数器意外没有 TTL）时设置窗口 TTL，返回 {计数, 是否新窗口}
+var readAndTickScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+local isNew = 0
+if count == 1 then
+	isNew = 1
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+elseif redis.call('PTTL', KEYS[1]) == -1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return {count, isNew}
+`)
+
+// ReadAndTick 原子地自增固定窗口计数器并返回新的计数，窗口的 TTL 只在第一次自增时设置（isNew 为 true），
+// 因此窗口边界精确地从窗口内第一个请求开始，避免 SET 后再 INCR 带来的 TTL 漂移
+func ReadAndTick(ctx context.Context, key string, window time.Duration) (count int64, isNew bool, err error) {
+	if err := checkKeys(key); err != nil {
+		return 0, false, err
+	}
+	if window < time.Millisecond {
+		// 窗口按毫秒传给 PEXPIRE，PEXPIRE 0 会直接删除计数器
+		return 0, false, fmt.Errorf("invalid tick window %v: must be at least 1ms", window)
+	}
+	result, err := readAndTickScript.Run(ctx, Client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to tick counter %s: %v", key, err)
+	}
+	invalidateLocal(key)
+	if len(result) != 2 {
+		return 0, false, fmt.Errorf("failed to tick counter %s: unexpected reply %v", key, result)
+	}
+	return result[0], result[1] == 1, nil
+}
@@ -0,0 +1,150 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Tier 表示 TieredCache 的缓存层
+type Tier int
+
+const (
+	// TierFar 是远端缓存（Redis）
+	TierFar Tier = iota
+	// TierNear 是进程内缓存
+	TierNear
+)
+
+// TieredCacheOptions 是 TieredCache 的配置
+type TieredCacheOptions struct {
+	NearSize int           // 进程内缓存容量，默认 10000
+	NearTTL  time.Duration // 进程内缓存条目的最长 TTL，0 表示不限制；条目不会比 Redis 中的 key 更晚过期
+
+	// ReadRepair 开启后，进程内缓存命中时仍会读取 Redis 比较两层的值，
+	// 不一致时以 Authoritative 一侧为准覆盖另一侧，并调用 OnDivergence。
+	// Redis 中 key 已不存在（过期或被删除）时总是删除进程内的条目，不会以进程内的值重建 key
+	ReadRepair    bool
+	Authoritative Tier // 以哪一层为准，默认 TierFar
+	// OnDivergence 在两层的值不一致时调用，far 为空表示 Redis 中已不存在该 key
+	OnDivergence func(key, near, far string)
+}
+
+// TieredCache 是进程内缓存（near）+ Redis（far）的两级字符串缓存
+// 读取时先查进程内缓存，未命中时读取 Redis 并提升到进程内缓存；写入时先写 Redis 再写进程内缓存
+type TieredCache struct {
+	near        *localCache
+	opts        TieredCacheOptions
+	divergences atomic.Int64
+}
+
+// NewTieredCache 创建两级缓存
+func NewTieredCache(opts TieredCacheOptions) *TieredCache {
+	return &TieredCache{near: newLocalCache(opts.NearSize), opts: opts}
+}
+
+// Get 读取 key，两层都不存在时返回 ErrKeyNotFound；Redis 不可用时如果进程内缓存命中则返回进程内的值
+func (c *TieredCache) Get(ctx context.Context, key string) (string, error) {
+	if err := checkKeys(key); err != nil {
+		return "", err
+	}
+	near, nearOK := c.near.Get(key)
+	if nearOK && !c.opts.ReadRepair {
+		return near, nil
+	}
+
+	var get *redis.StringCmd
+	var pttl *redis.DurationCmd
+	_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+	far, ttl := get.Val(), pttl.Val()
+	if err == redis.Nil {
+		if !nearOK {
+			return "", fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+		}
+		// key 在 Redis 中过期或被删除，即使以进程内为准也不重建，否则带 TTL 的 key 会变成永久 key
+		c.diverged(key, near, "")
+		c.near.Delete(key)
+		return "", fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	if err != nil {
+		if nearOK {
+			return near, nil
+		}
+		return "", fmt.Errorf("failed to get value of key %s: %v", key, err)
+	}
+
+	if !nearOK {
+		c.near.Set(key, far, c.nearTTL(ttl))
+		return far, nil
+	}
+	if near == far {
+		return far, nil
+	}
+
+	c.diverged(key, near, far)
+	if c.opts.Authoritative == TierNear {
+		if err := Client.SetArgs(ctx, key, near, redis.SetArgs{KeepTTL: true}).Err(); err != nil {
+			return "", fmt.Errorf("failed to repair key %s: %v", key, err)
+		}
+		return near, nil
+	}
+	c.near.Set(key, far, c.nearTTL(ttl))
+	return far, nil
+}
+
+// Set 写入 Redis 后写入进程内缓存，ttl 为 0 表示不过期
+func (c *TieredCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	if err := Client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set value of key %s: %v", key, err)
+	}
+	invalidateLocal(key)
+	c.near.Set(key, value, c.nearTTL(ttl))
+	return nil
+}
+
+// nearTTL 根据 Redis 中 key 的剩余 TTL（小于等于 0 表示不过期）计算进程内条目的 TTL，取其与 NearTTL 中较短的一个
+func (c *TieredCache) nearTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return c.opts.NearTTL
+	}
+	if c.opts.NearTTL > 0 && c.opts.NearTTL < ttl {
+		return c.opts.NearTTL
+	}
+	return ttl
+}
+
+// Delete 同时从两层删除 key
+func (c *TieredCache) Delete(ctx context.Context, key string) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	c.near.Delete(key)
+	if err := Client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to delete key %s: %v", key, err)
+	}
+	invalidateLocal(key)
+	return nil
+}
+
+// Divergences 返回开启 ReadRepair 以来发现的两层不一致次数
+func (c *TieredCache) Divergences() int64 {
+	return c.divergences.Load()
+}
+
+// diverged 记录一次不一致并调用 OnDivergence
+func (c *TieredCache) diverged(key, near, far string) {
+	c.divergences.Add(1)
+	if c.opts.OnDivergence != nil {
+		c.opts.OnDivergence(key, near, far)
+	}
+}
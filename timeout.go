@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultCommandTimeout 是每条命令（或每个 pipeline）的默认超时时间，默认 3s（与 go-redis 默认的 ReadTimeout 相同）
+// 超时优先级：ctx 自带的 deadline > WithTimeout 设置的超时 > DefaultCommandTimeout。
+// 包内创建的客户端关闭了 go-redis 自身的 ReadTimeout/WriteTimeout，超时完全由这里决定，因此 WithTimeout 可以设置比 3s 更长的超时；
+// 设置为 0 时没有 deadline 的命令可能无限等待。阻塞命令（BLPOP、带 BLOCK 的 XREAD/XREADGROUP、WAIT 等）不使用 DefaultCommandTimeout，
+// 由 go-redis 按阻塞时长加 10s 设置读超时，需要时通过 WithTimeout 设置
+var DefaultCommandTimeout = 3 * time.Second
+
+// blockingCommands 是总是阻塞的命令，XREAD/XREADGROUP 只在带 BLOCK 参数时阻塞，见 isBlocking
+var blockingCommands = map[string]bool{
+	"blpop":      true,
+	"brpop":      true,
+	"brpoplpush": true,
+	"blmove":     true,
+	"blmpop":     true,
+	"bzpopmin":   true,
+	"bzpopmax":   true,
+	"bzmpop":     true,
+	"wait":       true,
+	"waitaof":    true,
+}
+
+type timeoutKey struct{}
+
+// WithTimeout 返回一个携带单次调用超时的 context，用于让个别慢命令（如 BLPOP、大的 SORT）覆盖 DefaultCommandTimeout
+// 如果 ctx 本身已经有 deadline，则以 ctx 的 deadline 为准
+func WithTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, d)
+}
+
+// isBlocking 判断命令是否会在服务端阻塞，阻塞命令的等待时间通常比 DefaultCommandTimeout 长
+func isBlocking(cmd redis.Cmder) bool {
+	name := cmd.Name()
+	if blockingCommands[name] {
+		return true
+	}
+	if name != "xread" && name != "xreadgroup" {
+		return false
+	}
+	for _, arg := range cmd.Args() {
+		if s, ok := arg.(string); ok && strings.EqualFold(s, "block") {
+			return true
+		}
+	}
+	return false
+}
+
+// commandContext 按优先级为命令计算最终使用的 context，blocking 为 true 时不使用 DefaultCommandTimeout
+func commandContext(ctx context.Context, blocking bool) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	if d, ok := ctx.Value(timeoutKey{}).(time.Duration); ok && d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	if DefaultCommandTimeout > 0 && !blocking {
+		return context.WithTimeout(ctx, DefaultCommandTimeout)
+	}
+	return ctx, func() {}
+}
+
+// timeoutHook 在每条命令执行前应用 commandContext 计算出的超时
+// ClusterClient 上的 hook 不会作用于 ForEachMaster/MasterForKey 返回的节点客户端，Cluster 模式下还需要通过 OnNewNode 安装到每个节点
+type timeoutHook struct{}
+
+func (timeoutHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (timeoutHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, cancel := commandContext(ctx, isBlocking(cmd))
+		defer cancel()
+		return next(ctx, cmd)
+	}
+}
+
+func (timeoutHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		blocking := false
+		for _, cmd := range cmds {
+			if isBlocking(cmd) {
+				blocking = true
+				break
+			}
+		}
+		ctx, cancel := commandContext(ctx, blocking)
+		defer cancel()
+		return next(ctx, cmds)
+	}
+}
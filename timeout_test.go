@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestWithTimeoutExtendsPastDefaultReadTimeout(t *testing.T) {
+	ctx := setupTestRedis(t)
+
+	// DEBUG SLEEP 4 比 go-redis 默认的 3s ReadTimeout 更长，WithTimeout(10s) 下应当成功
+	err := Client.Do(WithTimeout(ctx, 10*time.Second), "debug", "sleep", "4").Err()
+	if err != nil && strings.Contains(err.Error(), "DEBUG command not allowed") {
+		t.Skipf("DEBUG is disabled on the server: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("DEBUG SLEEP 4 with WithTimeout(10s): %v", err)
+	}
+}
+
+func TestCommandContext(t *testing.T) {
+	old := DefaultCommandTimeout
+	DefaultCommandTimeout = time.Second
+	defer func() { DefaultCommandTimeout = old }()
+
+	within := func(ctx context.Context, want time.Duration) bool {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return false
+		}
+		remaining := time.Until(deadline)
+		return remaining <= want && remaining > want-100*time.Millisecond
+	}
+
+	ctx, cancel := commandContext(context.Background(), false)
+	defer cancel()
+	if !within(ctx, time.Second) {
+		t.Fatal("default timeout not applied")
+	}
+
+	ctx, cancel = commandContext(WithTimeout(context.Background(), 30*time.Second), false)
+	defer cancel()
+	if !within(ctx, 30*time.Second) {
+		t.Fatal("WithTimeout did not override the default timeout")
+	}
+
+	ctx, cancel = commandContext(context.Background(), true)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatal("blocking command got the default timeout")
+	}
+
+	parent, parentCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer parentCancel()
+	ctx, cancel = commandContext(WithTimeout(parent, 30*time.Second), false)
+	defer cancel()
+	if !within(ctx, 5*time.Second) {
+		t.Fatal("ctx deadline did not take precedence")
+	}
+}
+
+func TestIsBlocking(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		cmd  redis.Cmder
+		want bool
+	}{
+		{redis.NewCmd(ctx, "get", "k"), false},
+		{redis.NewCmd(ctx, "blpop", "k", 0), true},
+		{redis.NewCmd(ctx, "xread", "count", 1, "streams", "s", "0"), false},
+		{redis.NewCmd(ctx, "xread", "block", 5000, "streams", "s", "0"), true},
+	}
+	for _, tt := range tests {
+		if got := isBlocking(tt.cmd); got != tt.want {
+			t.Errorf("isBlocking(%v) = %v; want %v", tt.cmd.Args(), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidateChannel 是 RESP2 模式下接收失效消息的频道
+const invalidateChannel = "__redis__:invalidate"
+
+// ClientTrackingOptions 对应 CLIENT TRACKING 的可选参数
+type ClientTrackingOptions struct {
+	BCast    bool     // 广播模式，跟踪所有匹配 Prefixes 的 key，而不是只跟踪读取过的 key
+	Prefixes []string // 广播模式下的 key 前缀
+	OptIn    bool     // 只跟踪 CLIENT CACHING yes 之后读取的 key
+	OptOut   bool     // 不跟踪 CLIENT CACHING no 之后读取的 key
+	NoLoop   bool     // 不接收本连接自身修改导致的失效消息
+	Redirect int64    // 失效消息重定向到的客户端 ID，0 时使用 SubscribeInvalidations 建立的订阅连接
+}
+
+var (
+	trackingMu   sync.Mutex
+	trackingConn *redis.Conn
+
+	invalidationMu     sync.Mutex
+	invalidationClient *redis.Client
+	invalidationID     atomic.Int64 // 订阅 __redis__:invalidate 的连接的客户端 ID
+)
+
+// TrackingConn 返回用于客户端缓存的专用连接（首次调用时创建）
+// CLIENT TRACKING 是连接级别的，需要被跟踪的读取必须通过这个连接执行，连接池中的其他连接不受影响
+func TrackingConn() (*redis.Conn, error) {
+	if config.IsCluster {
+		return nil, ErrClusterUnsupported
+	}
+	trackingMu.Lock()
+	defer trackingMu.Unlock()
+	if trackingConn == nil {
+		trackingConn = Client.(*redis.Client).Conn()
+	}
+	return trackingConn, nil
+}
+
+// ClientTracking 在 TrackingConn 返回的专用连接上开启或关闭 CLIENT TRACKING
+// 不设置 REDIRECT 时，失效消息以 RESP3 push 的形式发送到开启跟踪的连接本身，这要求 RESP3，且当前 go-redis 不会把 push 消息交给调用方；
+// 因此默认将失效消息重定向到 SubscribeInvalidations 建立的 RESP2 订阅连接，需要先调用 SubscribeInvalidations，
+// 否则（且 opts.Redirect 为 0）开启跟踪会返回 ErrNoInvalidationRedirect。
+// 订阅连接断线重连后客户端 ID 会变化，需要重新调用 ClientTracking。Cluster 模式下返回 ErrClusterUnsupported
+func ClientTracking(ctx context.Context, on bool, opts ClientTrackingOptions) error {
+	conn, err := TrackingConn()
+	if err != nil {
+		return err
+	}
+
+	args := []interface{}{"client", "tracking", "off"}
+	if on {
+		args = []interface{}{"client", "tracking", "on"}
+		redirect := opts.Redirect
+		if redirect == 0 {
+			redirect = invalidationID.Load()
+		}
+		if redirect == 0 {
+			// 没有 REDIRECT 时失效消息会以 push 形式发到这个连接上，被当作后续命令的回复读取，破坏连接
+			return ErrNoInvalidationRedirect
+		}
+		args = append(args, "redirect", redirect)
+		if opts.BCast {
+			args = append(args, "bcast")
+		}
+		for _, prefix := range opts.Prefixes {
+			args = append(args, "prefix", prefix)
+		}
+		if opts.OptIn {
+			args = append(args, "optin")
+		}
+		if opts.OptOut {
+			args = append(args, "optout")
+		}
+		if opts.NoLoop {
+			args = append(args, "noloop")
+		}
+	}
+	cmd := redis.NewStatusCmd(ctx, args...)
+	if err := conn.Process(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to set client tracking: %v", err)
+	}
+	return nil
+}
+
+// SubscribeInvalidations 通过一个 RESP2 连接订阅 __redis__:invalidate，每收到一条失效消息就调用 handler
+// keys 为被失效的 key，nil 表示整个数据库被清空（FLUSHALL/FLUSHDB）。关闭返回的 PubSub 即可停止接收
+// Cluster 模式下返回 ErrClusterUnsupported
+func SubscribeInvalidations(ctx context.Context, handler func(keys []string)) (*redis.PubSub, error) {
+	if config.IsCluster {
+		return nil, ErrClusterUnsupported
+	}
+
+	invalidationMu.Lock()
+	if invalidationClient == nil {
+		opts := *Client.(*redis.Client).Options()
+		opts.Protocol = 2
+		opts.OnConnect = func(ctx context.Context, cn *redis.Conn) error {
+			id, err := cn.ClientID(ctx).Result()
+			if err != nil {
+				return err
+			}
+			invalidationID.Store(id)
+			return nil
+		}
+		invalidationClient = redis.NewClient(&opts)
+	}
+	invalidationMu.Unlock()
+
+	pubsub := invalidationClient.Subscribe(ctx, invalidateChannel)
+	// 等待订阅确认，确保订阅连接及其客户端 ID 已就绪
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %v", invalidateChannel, err)
+	}
+	go func() {
+		for msg := range pubsub.Channel() {
+			handler(msg.PayloadSlice)
+		}
+	}()
+	return pubsub, nil
+}
@@ -0,0 +1,201 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// EnforceTTLPolicy 扫描匹配 pattern 的 key，按 fn 返回的策略修正 TTL，返回被修改的 key 数量
+// fn 接收 key 当前的 TTL（没有过期时间时为 -1），返回 persist 为 true 时移除 TTL；
+// 否则 desiredTTL 大于 0 且与当前 TTL 不同时设置为 desiredTTL，desiredTTL 小于等于 0 时不做修改。
+// fn 为 nil 时使用默认策略：没有 TTL 的 key 设置为 wantTTL，其余不变。扫描期间被删除的 key 会被跳过
+func EnforceTTLPolicy(ctx context.Context, pattern string, wantTTL time.Duration, fn func(key string, currentTTL time.Duration) (desiredTTL time.Duration, persist bool)) (fixed int, err error) {
+	if err := checkDestructivePattern(pattern); err != nil {
+		return 0, err
+	}
+	if fn == nil {
+		fn = func(key string, currentTTL time.Duration) (time.Duration, bool) {
+			if currentTTL < 0 {
+				return wantTTL, false
+			}
+			return 0, false
+		}
+	}
+
+	var mu sync.Mutex
+	err = Scan(ctx, pattern, 100, func(keys []string) error {
+		ttls, err := pttls(ctx, keys)
+		if err != nil {
+			return err
+		}
+		for _, group := range groupKeysBySlot(keys) {
+			var cmds []*redis.BoolCmd
+			_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+				for _, key := range group {
+					current, ok := ttls[key]
+					if !ok {
+						continue
+					}
+					desired, persist := fn(key, current)
+					if persist {
+						if current >= 0 {
+							cmds = append(cmds, pipe.Persist(ctx, key))
+						}
+					} else if desired > 0 && desired != current {
+						cmds = append(cmds, pipe.PExpire(ctx, key, desired))
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply ttl policy: %v", err)
+			}
+			mu.Lock()
+			for _, cmd := range cmds {
+				if cmd.Val() {
+					fixed++
+				}
+			}
+			mu.Unlock()
+		}
+		return nil
+	})
+	return fixed, err
+}
+
+// pttls 按哈希槽分组 pipeline 执行 PTTL，返回 key -> TTL（没有过期时间时为 -1），不存在的 key 不会出现在结果中
+func pttls(ctx context.Context, keys []string) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration, len(keys))
+	for _, group := range groupKeysBySlot(keys) {
+		cmds := make([]*redis.DurationCmd, len(group))
+		_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, key := range group {
+				cmds[i] = pipe.PTTL(ctx, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ttl of keys: %v", err)
+		}
+		for i, key := range group {
+			switch ttl := cmds[i].Val(); {
+			case ttl == -2:
+				continue
+			case ttl < 0:
+				ttls[key] = -1
+			default:
+				ttls[key] = ttl
+			}
+		}
+	}
+	return ttls, nil
+}
+
+// ExtendTTLByPattern 扫描匹配 pattern 的 key，将每个带有过期时间的 key 的 TTL 延长 extraTTL，返回被延长的 key 数量
+// 没有过期时间的 key 保持不变；扫描期间被删除或过期的 key 会被跳过
+// 读取 TTL 与设置新 TTL 之间存在少量时间差，延长后的 TTL 可能比精确值略长
+func ExtendTTLByPattern(ctx context.Context, pattern string, extraTTL time.Duration) (int, error) {
+	if err := checkDestructivePattern(pattern); err != nil {
+		return 0, err
+	}
+	var mu sync.Mutex
+	extended := 0
+	err := Scan(ctx, pattern, 100, func(keys []string) error {
+		ttls, err := pttls(ctx, keys)
+		if err != nil {
+			return err
+		}
+		for _, group := range groupKeysBySlot(keys) {
+			var cmds []*redis.BoolCmd
+			_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+				for _, key := range group {
+					if ttl, ok := ttls[key]; ok && ttl > 0 {
+						cmds = append(cmds, pipe.PExpire(ctx, key, ttl+extraTTL))
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to extend ttl of keys: %v", err)
+			}
+			mu.Lock()
+			for _, cmd := range cmds {
+				if cmd.Val() {
+					extended++
+				}
+			}
+			mu.Unlock()
+		}
+		return nil
+	})
+	return extended, err
+}
+
+// ExpireAtByPattern 扫描匹配 pattern 的 key 并对每个 key 执行 EXPIREAT at，使它们在同一时刻过期，返回设置成功的数量
+// 命令按哈希槽分组 pipeline 执行；扫描期间被删除的 key 会被跳过
+func ExpireAtByPattern(ctx context.Context, pattern string, at time.Time) (int, error) {
+	if err := checkDestructivePattern(pattern); err != nil {
+		return 0, err
+	}
+	var mu sync.Mutex
+	expired := 0
+	err := Scan(ctx, pattern, 100, func(keys []string) error {
+		for _, group := range groupKeysBySlot(keys) {
+			cmds := make([]*redis.BoolCmd, len(group))
+			_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+				for i, key := range group {
+					cmds[i] = pipe.ExpireAt(ctx, key, at)
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set expire time of keys: %v", err)
+			}
+			mu.Lock()
+			for _, cmd := range cmds {
+				if cmd.Val() {
+					expired++
+				}
+			}
+			mu.Unlock()
+		}
+		return nil
+	})
+	return expired, err
+}
+
+// ExpireTime 返回 key 的绝对过期时间（EXPIRETIME，Redis 7+），key 不存在时返回 ErrKeyNotFound，没有过期时间时返回 ErrNoExpiry
+// 不支持 EXPIRETIME 的旧版本 Redis 回退为 当前时间 + PTTL，结果是近似值
+func ExpireTime(ctx context.Context, key string) (time.Time, error) {
+	if err := checkKeys(key); err != nil {
+		return time.Time{}, err
+	}
+	at, err := Client.ExpireTime(ctx, key).Result()
+	if isUnknownCommand(err) {
+		ttl, err := Client.PTTL(ctx, key).Result()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to get ttl of key %s: %v", key, err)
+		}
+		switch {
+		case ttl == -2:
+			return time.Time{}, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+		case ttl < 0:
+			return time.Time{}, fmt.Errorf("%w: %s", ErrNoExpiry, key)
+		}
+		return time.Now().Add(ttl), nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get expire time of key %s: %v", key, err)
+	}
+	switch {
+	case at == -2:
+		return time.Time{}, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	case at < 0:
+		return time.Time{}, fmt.Errorf("%w: %s", ErrNoExpiry, key)
+	}
+	return time.Unix(int64(at/time.Second), 0), nil
+}
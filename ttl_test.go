@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExpireTime(t *testing.T) {
+	ctx := setupTestRedis(t)
+	key := "test:expiretime"
+	cleanupKeys(t, ctx, key)
+
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := Client.Set(ctx, key, "v", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Client.ExpireAt(ctx, key, at).Err(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ExpireTime(ctx, key)
+	if err != nil {
+		t.Fatalf("ExpireTime: %v", err)
+	}
+	// 服务端不支持 EXPIRETIME 时通过 PTTL 计算，允许 1s 误差
+	if diff := got.Sub(at); diff < -time.Second || diff > time.Second {
+		t.Fatalf("ExpireTime = %v; want %v", got, at)
+	}
+}
+
+func TestExpireTimeNoExpiry(t *testing.T) {
+	ctx := setupTestRedis(t)
+	key := "test:expiretime:persistent"
+	cleanupKeys(t, ctx, key)
+
+	if err := Client.Set(ctx, key, "v", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExpireTime(ctx, key); !errors.Is(err, ErrNoExpiry) {
+		t.Fatalf("ExpireTime without expiry: err = %v; want ErrNoExpiry", err)
+	}
+}
+
+func TestExpireTimeMissingKey(t *testing.T) {
+	ctx := setupTestRedis(t)
+	key := "test:expiretime:missing"
+	cleanupKeys(t, ctx, key)
+
+	if _, err := ExpireTime(ctx, key); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("ExpireTime on missing key: err = %v; want ErrKeyNotFound", err)
+	}
+}
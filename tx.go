@@ -0,0 +1,49 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AtomicUpdate 对 keys 执行 WATCH 后调用 fn，fn 通过 tx 读取数据，并在 tx.TxPipelined 中排入写命令；
+// 被 WATCH 的 key 在此期间被修改时事务失败（redis.TxFailedErr），整个过程自动重试，最多 maxRetries 次
+// 重试用尽后返回包装了 redis.TxFailedErr 的错误；Cluster 模式下所有 key 必须位于同一个哈希槽
+//
+//	err := AtomicUpdate(ctx, []string{"a", "b"}, func(tx *redis.Tx) error {
+//		a, err := tx.Get(ctx, "a").Int()
+//		if err != nil && err != redis.Nil {
+//			return err
+//		}
+//		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+//			pipe.Set(ctx, "b", a+1, 0)
+//			return nil
+//		})
+//		return err
+//	}, 5)
+func AtomicUpdate(ctx context.Context, keys []string, fn func(tx *redis.Tx) error, maxRetries int) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("WATCH requires at least one key")
+	}
+	if err := checkKeys(keys...); err != nil {
+		return err
+	}
+	if err := checkSameSlot(keys...); err != nil {
+		return err
+	}
+	if maxRetries < 1 {
+		maxRetries = 1
+	}
+	for i := 0; i < maxRetries; i++ {
+		err := Client.Watch(ctx, fn, keys...)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to update keys %v after %d attempts: %w", keys, maxRetries, redis.TxFailedErr)
+}
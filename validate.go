@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkKeys 检查 key 参数均不为空
+func checkKeys(keys ...string) error {
+	for _, key := range keys {
+		if key == "" {
+			return ErrEmptyKey
+		}
+	}
+	return nil
+}
+
+// checkPattern 检查匹配模式不为空
+func checkPattern(pattern string) error {
+	if pattern == "" {
+		return ErrEmptyPattern
+	}
+	return nil
+}
+
+// checkDestructivePattern 用于会修改或删除 key 的批量操作：模式不能为空，
+// 只由通配符 * 和 ? 组成的模式（会匹配所有 key）需要在配置中开启 AllowDestructiveWildcard
+func checkDestructivePattern(pattern string) error {
+	if err := checkPattern(pattern); err != nil {
+		return err
+	}
+	if strings.Trim(pattern, "*?") == "" && !config.AllowDestructiveWildcard {
+		return fmt.Errorf("%w: %q", ErrWildcardPattern, pattern)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// versionedCASScript 当哈希中的 version（不存在时为 0）等于 ARGV[1] 时写入新值并将版本加一
+// ARGV[2] 为新值，ARGV[3] 为毫秒级 TTL，0 表示不过期
+var versionedCASScript = redis.NewScript(`
+local version = redis.call('HGET', KEYS[1], 'version') or '0'
+if version ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'value', ARGV[2], 'version', tonumber(ARGV[1]) + 1)
+local ttl = tonumber(ARGV[3])
+if ttl > 0 then
+	redis.call('PEXPIRE', KEYS[1], ttl)
+end
+return 1
+`)
+
+// VersionedCache 以哈希存储 JSON 序列化的值及单调递增的版本号，通过 Lua CAS 实现无锁的乐观并发更新
+type VersionedCache[T any] struct {
+	ttl        time.Duration
+	maxRetries int
+}
+
+// NewVersionedCache 创建带版本号的缓存，ttl 为 0 表示不过期，maxRetries 为版本冲突时的最大重试次数
+func NewVersionedCache[T any](ttl time.Duration, maxRetries int) *VersionedCache[T] {
+	if maxRetries < 1 {
+		maxRetries = 1
+	}
+	return &VersionedCache[T]{ttl: ttl, maxRetries: maxRetries}
+}
+
+// Get 返回当前值及版本号，key 不存在时返回 ErrKeyNotFound
+func (c *VersionedCache[T]) Get(ctx context.Context, key string) (T, int64, error) {
+	if err := checkKeys(key); err != nil {
+		var zero T
+		return zero, 0, err
+	}
+	value, version, exists, err := c.load(ctx, key)
+	if err != nil {
+		return value, 0, err
+	}
+	if !exists {
+		return value, 0, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	return value, version, nil
+}
+
+// Update 读取当前值与版本号（key 不存在时为零值与版本 0），调用 mutate 计算新值，仅当版本未变化时写回
+// 版本冲突时重新读取并重试，超过重试次数返回 ErrVersionConflict；mutate 返回错误时直接返回该错误
+func (c *VersionedCache[T]) Update(ctx context.Context, key string, mutate func(current T, version int64) (T, error)) (T, error) {
+	var zero T
+	if err := checkKeys(key); err != nil {
+		return zero, err
+	}
+	for i := 0; i < c.maxRetries; i++ {
+		current, version, _, err := c.load(ctx, key)
+		if err != nil {
+			return zero, err
+		}
+		next, err := mutate(current, version)
+		if err != nil {
+			return zero, err
+		}
+		data, err := json.Marshal(next)
+		if err != nil {
+			return zero, fmt.Errorf("failed to marshal value of key %s: %v", key, err)
+		}
+		swapped, err := versionedCASScript.Run(ctx, Client, []string{key}, version, data, c.ttl.Milliseconds()).Int()
+		if err != nil {
+			return zero, fmt.Errorf("failed to update key %s: %v", key, err)
+		}
+		if swapped == 1 {
+			return next, nil
+		}
+	}
+	return zero, fmt.Errorf("%w: key %s after %d attempts", ErrVersionConflict, key, c.maxRetries)
+}
+
+// load 读取值与版本号，exists 表示 key 是否存在
+func (c *VersionedCache[T]) load(ctx context.Context, key string) (value T, version int64, exists bool, err error) {
+	fields, err := Client.HMGet(ctx, key, "value", "version").Result()
+	if err != nil {
+		return value, 0, false, fmt.Errorf("failed to get key %s: %v", key, err)
+	}
+	data, ok := fields[0].(string)
+	if !ok {
+		return value, 0, false, nil
+	}
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		return value, 0, false, fmt.Errorf("failed to unmarshal value of key %s: %v", key, err)
+	}
+	if v, ok := fields[1].(string); ok {
+		version, _ = strconv.ParseInt(v, 10, 64)
+	}
+	return value, version, true, nil
+}
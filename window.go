@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TimeWindow 基于有序集合的时间窗口，score 为事件的毫秒时间戳
+// 有序集合的成员是唯一的，相同的 event 重复写入只会更新时间戳，需要计数时请在 event 中带上唯一 ID
+type TimeWindow struct {
+	key       string
+	retention time.Duration
+	err       error // 参数不合法时由 NewTimeWindow 设置，所有方法直接返回该错误
+}
+
+// NewTimeWindow 创建时间窗口，早于 retention 的事件会在写入时被顺带清理
+// key 为空时各方法返回 ErrEmptyKey；retention 小于 1ms 时各方法返回错误（否则 Add 写入后会立即删除整个 key）
+func NewTimeWindow(key string, retention time.Duration) *TimeWindow {
+	w := &TimeWindow{key: key, retention: retention}
+	if err := checkKeys(key); err != nil {
+		w.err = err
+	} else if retention < time.Millisecond {
+		w.err = fmt.Errorf("invalid retention %v of window %s: must be at least 1ms", retention, key)
+	}
+	return w
+}
+
+// Add 记录一个发生在 at 时刻的事件，并清理超出保留时长的旧事件
+func (w *TimeWindow) Add(ctx context.Context, event string, at time.Time) error {
+	if err := w.err; err != nil {
+		return err
+	}
+	_, err := Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, w.key, redis.Z{Score: float64(at.UnixMilli()), Member: event})
+		pipe.ZRemRangeByScore(ctx, w.key, "-inf", w.cutoff(w.retention))
+		pipe.PExpire(ctx, w.key, w.retention)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add event to window %s: %v", w.key, err)
+	}
+	return nil
+}
+
+// Count 返回最近 since 时间内的事件数量
+func (w *TimeWindow) Count(ctx context.Context, since time.Duration) (int64, error) {
+	if err := w.err; err != nil {
+		return 0, err
+	}
+	result, err := Client.ZCount(ctx, w.key, w.since(since), "+inf").Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count events of window %s: %v", w.key, err)
+	}
+	return result, nil
+}
+
+// Recent 返回最近 since 时间内的事件，按时间从旧到新排列
+func (w *TimeWindow) Recent(ctx context.Context, since time.Duration) ([]string, error) {
+	if err := w.err; err != nil {
+		return nil, err
+	}
+	result, err := Client.ZRangeByScore(ctx, w.key, &redis.ZRangeBy{Min: w.since(since), Max: "+inf"}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent events of window %s: %v", w.key, err)
+	}
+	return result, nil
+}
+
+// Trim 主动清理超出保留时长的事件，返回清理的数量
+func (w *TimeWindow) Trim(ctx context.Context) (int64, error) {
+	if err := w.err; err != nil {
+		return 0, err
+	}
+	result, err := Client.ZRemRangeByScore(ctx, w.key, "-inf", w.cutoff(w.retention)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to trim window %s: %v", w.key, err)
+	}
+	return result, nil
+}
+
+// since 返回 d 时间之前（含）的最小 score
+func (w *TimeWindow) since(d time.Duration) string {
+	return strconv.FormatInt(time.Now().Add(-d).UnixMilli(), 10)
+}
+
+// cutoff 返回 d 时间之前（不含）的最大 score，用于删除更旧的事件
+func (w *TimeWindow) cutoff(d time.Duration) string {
+	return "(" + w.since(d)
+}
@@ -0,0 +1,106 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ZMPop 从 keys 中第一个非空的有序集合弹出最多 count 个成员，order 为 "min" 或 "max"
+// 返回弹出成员所在的 key；所有有序集合都为空时返回空 key 和 nil
+// Cluster 模式下所有 key 必须位于同一个哈希槽；不支持 ZMPOP 的旧版本 Redis 会依次尝试每个 key 的 ZPOPMIN/ZPOPMAX
+func ZMPop(ctx context.Context, order string, count int64, keys ...string) (key string, members []redis.Z, err error) {
+	order = strings.ToUpper(order)
+	if order != "MIN" && order != "MAX" {
+		return "", nil, fmt.Errorf("invalid order %q: must be min or max", order)
+	}
+	if len(keys) == 0 {
+		return "", nil, fmt.Errorf("ZMPOP requires at least one key")
+	}
+	if err := checkKeys(keys...); err != nil {
+		return "", nil, err
+	}
+	if err := checkSameSlot(keys...); err != nil {
+		return "", nil, err
+	}
+
+	cmd := withFirstKey(redis.NewZSliceWithKeyCmd(ctx, numKeysArgs("zmpop", keys, order, "count", count)...))
+	_ = Client.Process(ctx, cmd)
+	key, members, err = cmd.Result()
+	if err == redis.Nil {
+		return "", nil, nil
+	}
+	if err == nil {
+		return key, members, nil
+	}
+	if !isUnknownCommand(err) {
+		return "", nil, fmt.Errorf("failed to pop from sorted sets %v: %v", keys, err)
+	}
+
+	for _, key := range keys {
+		var cmd *redis.ZSliceCmd
+		if order == "MIN" {
+			cmd = Client.ZPopMin(ctx, key, count)
+		} else {
+			cmd = Client.ZPopMax(ctx, key, count)
+		}
+		members, err := cmd.Result()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to pop from sorted set %s: %v", key, err)
+		}
+		if len(members) > 0 {
+			return key, members, nil
+		}
+	}
+	return "", nil, nil
+}
+
+// ZInterCard 返回多个有序集合交集的成员数量（ZINTERCARD，Redis 7+），limit 大于 0 时达到 limit 即停止计算
+// Cluster 模式下所有 key 必须位于同一个哈希槽。不支持 ZINTERCARD 的旧版本 Redis 回退为
+// 在同一哈希槽的临时 key 上执行 ZINTERSTORE + ZCARD 后删除临时 key（在一个 MULTI 中完成）
+func ZInterCard(ctx context.Context, limit int64, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, fmt.Errorf("ZINTERCARD requires at least one key")
+	}
+	if err := checkKeys(keys...); err != nil {
+		return 0, err
+	}
+	if err := checkSameSlot(keys...); err != nil {
+		return 0, err
+	}
+
+	cmd := withFirstKey(redis.NewIntCmd(ctx, numKeysArgs("zintercard", keys, "limit", limit)...))
+	_ = Client.Process(ctx, cmd)
+	count, err := cmd.Result()
+	if err == nil {
+		return count, nil
+	}
+	if !isUnknownCommand(err) {
+		return 0, fmt.Errorf("failed to count intersection of sorted sets %v: %v", keys, err)
+	}
+
+	tmp := "{" + hashTag(keys[0]) + "}:zintercard:" + strconv.FormatInt(rand.Int63(), 36)
+	if config.IsCluster && keySlot(tmp) != keySlot(keys[0]) {
+		// 如 "a{}b" 没有有效的 hashtag，整个 key 参与哈希，加上花括号后无法得到同一哈希槽的临时 key
+		return 0, fmt.Errorf("%w: cannot build a temporary key in the slot of %s for the ZINTERSTORE fallback", ErrCrossSlot, keys[0])
+	}
+	var card *redis.IntCmd
+	_, err = Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZInterStore(ctx, tmp, &redis.ZStore{Keys: keys})
+		card = pipe.ZCard(ctx, tmp)
+		pipe.Del(ctx, tmp)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count intersection of sorted sets %v: %v", keys, err)
+	}
+	count = card.Val()
+	if limit > 0 && count > limit {
+		count = limit
+	}
+	return count, nil
+}